@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
+	// Embedded zone database so TZ works in the distroless image (schedule evaluation).
+	_ "time/tzdata"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -24,12 +27,35 @@ func main() {
 		log.Fatalf("load config: %v", err)
 	}
 
-	client, err := bbox.NewClient(cfg.BBoxAPIURL, cfg.BBoxPassword)
+	tlsOpts := bbox.TLSOptions{
+		CAFile:             cfg.BBoxTLSCAFile,
+		PinnedSHA256:       cfg.BBoxTLSPinnedSHA256,
+		ServerName:         cfg.BBoxTLSServerName,
+		InsecureSkipVerify: cfg.BBoxTLSInsecureSkipVerify,
+	}
+	client, err := bbox.NewClient(cfg.BBoxAPIURL, cfg.BBoxPassword, bbox.Options{
+		MaxRetries:       cfg.BBoxAPIMaxRetries,
+		RequestTimeout:   time.Duration(cfg.BBoxAPIRequestTimeout) * time.Second,
+		RetryBaseDelay:   time.Duration(cfg.BBoxAPIRetryBaseDelayMs) * time.Millisecond,
+		CircuitThreshold: cfg.BBoxAPICircuitThreshold,
+		CircuitCooldown:  time.Duration(cfg.BBoxAPICircuitOpenTime) * time.Second,
+		TLS:              tlsOpts,
+	})
 	if err != nil {
 		log.Fatalf("init BBox client: %v", err)
 	}
+	if strings.HasPrefix(strings.ToLower(cfg.BBoxAPIURL), "https://") {
+		log.Printf("BBox TLS: %s", tlsOpts.Mode())
+	} else if tlsOpts.Configured() {
+		log.Printf("BBox TLS: options ignored, BBoxAPIURL does not use https")
+	}
 
-	exp := exporter.New(client)
+	exp := exporter.New(client, exporter.Options{
+		TopTalkers:       cfg.TopTalkersCount,
+		ForwardDeviceLog: cfg.ForwardDeviceLog,
+		Workers:          cfg.BBoxAPIConcurrency,
+		EndpointTimeout:  time.Duration(cfg.BBoxAPIEndpointTimeout) * time.Second,
+	})
 	refreshInterval := time.Duration(cfg.BBoxAPIRefreshTime) * time.Second
 
 	if err := exp.Refresh(context.Background()); err != nil {
@@ -46,6 +72,18 @@ func main() {
 		}
 	}()
 
+	if cfg.WirelessScanRefreshTime > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.WirelessScanRefreshTime) * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := exp.RefreshWirelessScan(context.Background()); err != nil {
+					log.Printf("wireless scan failed: %v", err)
+				}
+			}
+		}()
+	}
+
 	http.Handle("/metrics", promhttp.Handler())
 	addr := fmt.Sprintf(":%d", cfg.MetricsServerListeningPort)
 	log.Printf("serving metrics at %s/metrics", addr)
@@ -0,0 +1,118 @@
+package bbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitThreshold = 5
+	defaultCircuitCooldown  = 30 * time.Second
+	// probeRoute is cheap and answers even without a session.
+	probeRoute = "/api/v1/device"
+)
+
+// CircuitState is the state of the client circuit breaker, numbered as exported by
+// bb_exporter_api_circuit_state.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitHalfOpen
+	CircuitOpen
+)
+
+// ErrCircuitOpen is returned without contacting the BBox while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit open, BBox API unavailable")
+
+// breaker opens after threshold consecutive failed requests. Once cooldown has elapsed the
+// next request probes the BBox (half-open) and closes the circuit if it answers.
+type breaker struct {
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+// CircuitState reports the current breaker state.
+func (c *Client) CircuitState() CircuitState {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.state
+}
+
+// allow lets a request through while the circuit is closed, probing the BBox first when an
+// open circuit has cooled down. Concurrent requests fail fast while the probe runs.
+func (c *Client) allow(ctx context.Context) error {
+	b := &c.breaker
+	b.mu.Lock()
+	switch {
+	case b.state == CircuitClosed:
+		b.mu.Unlock()
+		return nil
+	case b.state == CircuitHalfOpen, time.Since(b.openedAt) < b.cooldown:
+		b.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	b.state = CircuitHalfOpen
+	b.mu.Unlock()
+
+	err := c.probe(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		return fmt.Errorf("%w (probe: %v)", ErrCircuitOpen, err)
+	}
+	b.state = CircuitClosed
+	b.failures = 0
+	return nil
+}
+
+// record counts the outcome of a request that went through allow.
+func (c *Client) record(err error) {
+	b := &c.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !unavailable(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitClosed && b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// probe checks that the BBox answers at all; any response below 500 means it is back.
+func (c *Client) probe(ctx context.Context) error {
+	_, err := c.doGet(ctx, probeRoute)
+	if unavailable(err) {
+		return err
+	}
+	return nil
+}
+
+// unavailable reports errors showing the BBox cannot be reached or is failing. Rejected
+// credentials, lockouts, 4xx answers and the caller's own cancellation prove it is up.
+func unavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var authErr *AuthError
+	var lockErr *LockoutError
+	return !errors.As(err, &authErr) && !errors.As(err, &lockErr)
+}
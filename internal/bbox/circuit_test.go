@@ -0,0 +1,123 @@
+package bbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitStateMachine(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	var down atomic.Bool
+	var hits atomic.Int32
+	down.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[{}]`))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "secret", Options{MaxRetries: -1, CircuitThreshold: 2, CircuitCooldown: cooldown})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	// Opens after the threshold of consecutive failures.
+	for i := range 2 {
+		if state := c.CircuitState(); state != CircuitClosed {
+			t.Fatalf("state before failure %d = %v, want closed", i, state)
+		}
+		if _, err := c.FetchCPU(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("failure %d: error = %v, want a 503", i, err)
+		}
+	}
+	if state := c.CircuitState(); state != CircuitOpen {
+		t.Fatalf("state after threshold = %v, want open", state)
+	}
+
+	// Fails fast without contacting the BBox while open.
+	before := hits.Load()
+	if _, err := c.FetchCPU(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("open circuit error = %v, want ErrCircuitOpen", err)
+	}
+	if got := hits.Load(); got != before {
+		t.Errorf("open circuit sent %d requests, want 0", got-before)
+	}
+
+	// A failed half-open probe re-opens the circuit.
+	time.Sleep(cooldown + 10*time.Millisecond)
+	before = hits.Load()
+	if _, err := c.FetchCPU(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("failed probe error = %v, want ErrCircuitOpen", err)
+	}
+	if got := hits.Load(); got != before+1 {
+		t.Errorf("failed probe sent %d requests, want 1", got-before)
+	}
+	if state := c.CircuitState(); state != CircuitOpen {
+		t.Fatalf("state after failed probe = %v, want open", state)
+	}
+
+	// A successful probe closes it and lets the request through.
+	down.Store(false)
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if _, err := c.FetchCPU(ctx); err != nil {
+		t.Fatalf("fetch after recovery: %v", err)
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Fatalf("state after successful probe = %v, want closed", state)
+	}
+}
+
+func TestCircuitIgnoresAuthFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "wrong", Options{CircuitThreshold: 1})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for range 3 {
+		var authErr *AuthError
+		if _, err := c.FetchCPU(context.Background()); !errors.As(err, &authErr) {
+			t.Fatalf("error = %v, want AuthError", err)
+		}
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Errorf("state = %v, want closed: a rejected password proves the BBox is up", state)
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"cancelled", fmt.Errorf("request: %w", context.Canceled), false},
+		{"deadline", fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"500", &StatusError{StatusCode: http.StatusInternalServerError}, true},
+		{"404", &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"auth", &AuthError{StatusCode: http.StatusUnauthorized}, false},
+		{"lockout", &LockoutError{Until: time.Now()}, false},
+		{"transport", errors.New("dial tcp: connection refused"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unavailable(tt.err); got != tt.want {
+				t.Errorf("unavailable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
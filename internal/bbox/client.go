@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,16 +20,54 @@ const (
 	logoutRoute    = "/api/v1/logout"
 	committedBug   = "committedas\":\n\t}"
 	committedFix   = "committedas\":0\n\t}"
-	defaultTimeout = 10 * time.Second
+	defaultTimeout = 5 * time.Second
+	// defaultLoginRetryDelay keeps a rejected password from being retried more often than a
+	// refresh would, so the BBox brute-force lockout is not triggered.
+	defaultLoginRetryDelay = 30 * time.Second
 )
 
+// Options tunes how the client copes with an unreliable BBox.
+type Options struct {
+	// MaxRetries is how many times a request failing transiently (timeout, reset, 5xx) is retried.
+	MaxRetries int
+	// RequestTimeout bounds a single HTTP attempt. Keep it well below the caller's deadline so a
+	// timed-out attempt leaves time for a retry. Zero means 5s.
+	RequestTimeout time.Duration
+	// RetryBaseDelay is the first backoff delay, doubled on every retry. Zero means 500ms.
+	RetryBaseDelay time.Duration
+	// CircuitThreshold is the number of consecutive failed requests that opens the circuit.
+	// Zero means 5.
+	CircuitThreshold int
+	// CircuitCooldown is how long requests fail fast before the BBox is probed again.
+	// Zero means 30s.
+	CircuitCooldown time.Duration
+	// LoginRetryDelay is how long a rejected password is reported without sending another
+	// login. Zero means 30s.
+	LoginRetryDelay time.Duration
+	// TLS controls verification of the BBox certificate.
+	TLS TLSOptions
+}
+
 type Client struct {
 	baseURL    string
 	password   string
 	httpClient *http.Client
+	opts       Options
+
+	// mu guards the session state below; see session.go.
+	mu            sync.Mutex
+	authenticated bool
+	generation    uint64
+	inflight      *loginCall
+	lockedUntil   time.Time
+	authErr       *AuthError
+	authRetryAt   time.Time
+	observer      func(route string, elapsed time.Duration)
+
+	breaker breaker
 }
 
-func NewClient(baseURL, password string) (*Client, error) {
+func NewClient(baseURL, password string, opts Options) (*Client, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("baseURL is required")
 	}
@@ -40,17 +80,58 @@ func NewClient(baseURL, password string) (*Client, error) {
 		return nil, fmt.Errorf("init cookie jar: %w", err)
 	}
 
+	tlsConfig, err := opts.TLS.config()
+	if err != nil {
+		return nil, fmt.Errorf("init TLS: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaultTimeout
+	}
+	if opts.LoginRetryDelay <= 0 {
+		opts.LoginRetryDelay = defaultLoginRetryDelay
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if opts.CircuitThreshold <= 0 {
+		opts.CircuitThreshold = defaultCircuitThreshold
+	}
+	if opts.CircuitCooldown <= 0 {
+		opts.CircuitCooldown = defaultCircuitCooldown
+	}
+
 	return &Client{
 		baseURL:  strings.TrimSuffix(baseURL, "/"),
 		password: password,
 		httpClient: &http.Client{
-			Jar:     jar,
-			Timeout: defaultTimeout,
+			Jar:       jar,
+			Timeout:   opts.RequestTimeout,
+			Transport: transport,
+		},
+		opts: opts,
+		breaker: breaker{
+			threshold: opts.CircuitThreshold,
+			cooldown:  opts.CircuitCooldown,
 		},
 	}, nil
 }
 
-func (c *Client) Login(ctx context.Context) error {
+// login posts the password; callers go through Login or relogin so concurrent logins are shared.
+// While the BBox lockout runs, it fails with a *LockoutError without contacting the box.
+func (c *Client) login(ctx context.Context) error {
+	c.mu.Lock()
+	until := c.lockedUntil
+	c.mu.Unlock()
+	if time.Now().Before(until) {
+		return &LockoutError{Until: until}
+	}
+
 	form := url.Values{}
 	form.Set("password", c.password)
 
@@ -68,12 +149,27 @@ func (c *Client) Login(ctx context.Context) error {
 
 	if resp.StatusCode >= http.StatusBadRequest {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return fmt.Errorf("login failed: status=%d body=%s", resp.StatusCode, string(body))
+		if lockedOut(resp, body) {
+			until := time.Now().Add(lockoutDelay(resp, body))
+			c.mu.Lock()
+			c.lockedUntil = until
+			c.mu.Unlock()
+			return &LockoutError{Until: until}
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return &StatusError{Route: loginRoute, StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		return &AuthError{Route: loginRoute, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	return nil
 }
 
+// Logout ends the current session; the next request logs in again.
 func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	c.authenticated = false
+	c.mu.Unlock()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(logoutRoute), http.NoBody)
 	if err != nil {
 		return fmt.Errorf("create logout request: %w", err)
@@ -87,6 +183,14 @@ func (c *Client) Logout(ctx context.Context) error {
 	return nil
 }
 
+func (c *Client) FetchDevice(ctx context.Context) (DeviceInfo, error) {
+	return fetchSingle[DeviceInfo](ctx, c, "/api/v1/device")
+}
+
+func (c *Client) FetchDeviceLog(ctx context.Context) (DeviceLog, error) {
+	return fetchSingle[DeviceLog](ctx, c, "/api/v1/device/log")
+}
+
 func (c *Client) FetchCPU(ctx context.Context) (DeviceCPU, error) {
 	return fetchSingle[DeviceCPU](ctx, c, "/api/v1/device/cpu")
 }
@@ -103,16 +207,114 @@ func (c *Client) FetchWanIPInfo(ctx context.Context) (WanIPInfo, error) {
 	return fetchSingle[WanIPInfo](ctx, c, "/api/v1/wan/ip")
 }
 
+func (c *Client) FetchWanFTTH(ctx context.Context) (WanFTTH, error) {
+	return fetchSingle[WanFTTH](ctx, c, "/api/v1/wan/ftth")
+}
+
+func (c *Client) FetchWanBackup(ctx context.Context) (WanBackup, error) {
+	return fetchSingle[WanBackup](ctx, c, "/api/v1/wan/autowan")
+}
+
 func (c *Client) FetchLanStats(ctx context.Context) (LanStats, error) {
 	return fetchSingle[LanStats](ctx, c, "/api/v1/lan/stats")
 }
 
-func (c *Client) FetchWireless24Stats(ctx context.Context) (WirelessStats, error) {
-	return fetchSingle[WirelessStats](ctx, c, "/api/v1/wireless/24/stats")
+func (c *Client) FetchLanPorts(ctx context.Context) (LanPorts, error) {
+	return fetchSingle[LanPorts](ctx, c, "/api/v1/lan/ports")
+}
+
+func (c *Client) FetchWirelessConfig(ctx context.Context) (WirelessConfig, error) {
+	return fetchSingle[WirelessConfig](ctx, c, "/api/v1/wireless")
+}
+
+// FetchWirelessNeighborhood lists access points seen by the radio of the given band ("24", "5", ...).
+func (c *Client) FetchWirelessNeighborhood(ctx context.Context, band string) (WirelessNeighborhood, error) {
+	return fetchSingle[WirelessNeighborhood](ctx, c, "/api/v1/wireless/"+url.PathEscape(band)+"/neighborhood")
+}
+
+// FetchWirelessStats returns the SSID counters of the radio for the given band ("24", "5", "6", ...).
+func (c *Client) FetchWirelessStats(ctx context.Context, band string) (WirelessStats, error) {
+	return fetchSingle[WirelessStats](ctx, c, "/api/v1/wireless/"+url.PathEscape(band)+"/stats")
+}
+
+func (c *Client) FetchHosts(ctx context.Context) (Hosts, error) {
+	return fetchSingle[Hosts](ctx, c, "/api/v1/hosts")
+}
+
+func (c *Client) FetchDHCP(ctx context.Context) (DHCPConfig, error) {
+	return fetchSingle[DHCPConfig](ctx, c, "/api/v1/dhcp")
+}
+
+func (c *Client) FetchDHCPClients(ctx context.Context) (DHCPClients, error) {
+	return fetchSingle[DHCPClients](ctx, c, "/api/v1/dhcp/clients")
+}
+
+func (c *Client) FetchXDSLInfo(ctx context.Context) (XDSLInfo, error) {
+	return fetchSingle[XDSLInfo](ctx, c, "/api/v1/xdsl")
+}
+
+func (c *Client) FetchXDSLStats(ctx context.Context) (XDSLStats, error) {
+	return fetchSingle[XDSLStats](ctx, c, "/api/v1/xdsl/stats")
+}
+
+func (c *Client) FetchVoIP(ctx context.Context) (VoIP, error) {
+	return fetchSingle[VoIP](ctx, c, "/api/v1/voip")
 }
 
-func (c *Client) FetchWireless5Stats(ctx context.Context) (WirelessStats, error) {
-	return fetchSingle[WirelessStats](ctx, c, "/api/v1/wireless/5/stats")
+func (c *Client) FetchCallLog(ctx context.Context) (CallLog, error) {
+	return fetchSingle[CallLog](ctx, c, "/api/v1/voip/fullcalllog")
+}
+
+func (c *Client) FetchNATRules(ctx context.Context) (NATRules, error) {
+	return fetchSingle[NATRules](ctx, c, "/api/v1/nat/rules")
+}
+
+func (c *Client) FetchUPnPMappings(ctx context.Context) (UPnPMappings, error) {
+	return fetchSingle[UPnPMappings](ctx, c, "/api/v1/upnp/igd")
+}
+
+func (c *Client) FetchFirewallRules(ctx context.Context) (FirewallRules, error) {
+	return fetchSingle[FirewallRules](ctx, c, "/api/v1/firewall/rules")
+}
+
+func (c *Client) FetchFirewallPinholes(ctx context.Context) (FirewallPinholes, error) {
+	return fetchSingle[FirewallPinholes](ctx, c, "/api/v1/firewall/pinhole")
+}
+
+func (c *Client) FetchIPTV(ctx context.Context) (IPTV, error) {
+	return fetchSingle[IPTV](ctx, c, "/api/v1/iptv")
+}
+
+func (c *Client) FetchWanIPTV(ctx context.Context) (WanIPTV, error) {
+	return fetchSingle[WanIPTV](ctx, c, "/api/v1/wan/iptv")
+}
+
+func (c *Client) FetchUSB(ctx context.Context) (USB, error) {
+	return fetchSingle[USB](ctx, c, "/api/v1/usb")
+}
+
+func (c *Client) FetchServices(ctx context.Context) (Services, error) {
+	return fetchSingle[Services](ctx, c, "/api/v1/services")
+}
+
+func (c *Client) FetchDynDNS(ctx context.Context) (DynDNS, error) {
+	return fetchSingle[DynDNS](ctx, c, "/api/v1/dyndns")
+}
+
+func (c *Client) FetchParentalControl(ctx context.Context) (ParentalControl, error) {
+	return fetchSingle[ParentalControl](ctx, c, "/api/v1/parentalcontrol")
+}
+
+func (c *Client) FetchParentalScheduler(ctx context.Context) (ParentalScheduler, error) {
+	return fetchSingle[ParentalScheduler](ctx, c, "/api/v1/parentalcontrol/scheduler")
+}
+
+func (c *Client) FetchWirelessScheduler(ctx context.Context) (WirelessScheduler, error) {
+	return fetchSingle[WirelessScheduler](ctx, c, "/api/v1/wireless/scheduler")
+}
+
+func (c *Client) FetchRepeaters(ctx context.Context) (Repeaters, error) {
+	return fetchSingle[Repeaters](ctx, c, "/api/v1/wireless/repeater")
 }
 
 func fetchSingle[T any](ctx context.Context, c *Client, route string) (T, error) {
@@ -136,7 +338,65 @@ func fetchSingle[T any](ctx context.Context, c *Client, route string) (T, error)
 	return payload[0], nil
 }
 
+// get performs a GET through the circuit breaker, failing fast with ErrCircuitOpen while
+// the BBox is considered unavailable.
 func (c *Client) get(ctx context.Context, route string) ([]byte, error) {
+	if err := c.allow(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", route, err)
+	}
+	start := time.Now()
+	body, err := c.getSession(ctx, route)
+	c.record(err)
+
+	c.mu.Lock()
+	observe := c.observer
+	c.mu.Unlock()
+	if observe != nil {
+		observe(route, time.Since(start))
+	}
+	return body, err
+}
+
+// ObserveRequests registers fn to receive the duration of every API request, retries and
+// re-login included. Requests failing fast on an open circuit are not reported.
+func (c *Client) ObserveRequests(fn func(route string, elapsed time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observer = fn
+}
+
+// getSession performs an authenticated GET, logging in first if needed and once more if the
+// BBox reports the session as expired.
+func (c *Client) getSession(ctx context.Context, route string) ([]byte, error) {
+	gen, err := c.ensureSession(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("login: %w", err)
+	}
+
+	body, err := c.getWithRetry(ctx, route)
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		return body, err
+	}
+
+	c.invalidate(gen)
+	if err := c.relogin(ctx, gen); err != nil {
+		return nil, fmt.Errorf("re-authenticate for %s: %w", route, err)
+	}
+	return c.getWithRetry(ctx, route)
+}
+
+func (c *Client) getWithRetry(ctx context.Context, route string) ([]byte, error) {
+	var body []byte
+	err := c.retry(ctx, func() error {
+		var err error
+		body, err = c.doGet(ctx, route)
+		return err
+	})
+	return body, err
+}
+
+func (c *Client) doGet(ctx context.Context, route string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(route), nil)
 	if err != nil {
 		return nil, fmt.Errorf("build request for %s: %w", route, err)
@@ -148,9 +408,12 @@ func (c *Client) get(ctx context.Context, route string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
+	if sessionExpired(resp) {
+		return nil, &AuthError{Route: route, StatusCode: resp.StatusCode}
+	}
 	if resp.StatusCode >= http.StatusBadRequest {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return nil, fmt.Errorf("status %d for %s body=%s", resp.StatusCode, route, string(body))
+		return nil, &StatusError{Route: route, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	body, err := io.ReadAll(resp.Body)
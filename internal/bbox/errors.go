@@ -0,0 +1,38 @@
+package bbox
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuthError reports a login rejected by the BBox or a session it no longer accepts.
+type AuthError struct {
+	Route      string
+	StatusCode int
+	Body       string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication rejected for %s: status=%d body=%s", e.Route, e.StatusCode, e.Body)
+}
+
+// LockoutError reports that the BBox refuses logins after too many failures. No login is
+// attempted before Until.
+type LockoutError struct {
+	Until time.Time
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("login locked out by the BBox until %s", e.Until.Format(time.RFC3339))
+}
+
+// StatusError reports any other HTTP error status returned by the API.
+type StatusError struct {
+	Route      string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d for %s body=%s", e.StatusCode, e.Route, e.Body)
+}
@@ -1,6 +1,11 @@
 package bbox
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
 
 // DeviceCPU mirrors /api/v1/device/cpu payload.
 type DeviceCPU struct {
@@ -53,6 +58,52 @@ type MemoryStats struct {
 	CommittedAs int `json:"committedas"`
 }
 
+// DeviceInfo mirrors /api/v1/device payload.
+type DeviceInfo struct {
+	Device DeviceDetails `json:"device"`
+}
+
+type DeviceDetails struct {
+	Now            string         `json:"now"`
+	Status         int            `json:"status"`
+	NumberOfBoots  FlexibleInt    `json:"numberofboots"`
+	ModelName      string         `json:"modelname"`
+	UserConfigured int            `json:"user_configured"`
+	Main           DeviceFirmware `json:"main"`
+	Reco           DeviceFirmware `json:"reco"`
+	Running        DeviceFirmware `json:"running"`
+	BCCK           DeviceFirmware `json:"bcck"`
+	FirstUseDate   string         `json:"firstusedate"`
+	Uptime         FlexibleInt    `json:"uptime"`
+	SerialNumber   string         `json:"serialnumber"`
+	Using          DeviceUsing    `json:"using"`
+}
+
+type DeviceFirmware struct {
+	Version string `json:"version"`
+	Date    string `json:"date"`
+}
+
+type DeviceUsing struct {
+	IPv4 int `json:"ipv4"`
+	IPv6 int `json:"ipv6"`
+	FTTH int `json:"ftth"`
+	ADSL int `json:"adsl"`
+	VDSL int `json:"vdsl"`
+}
+
+// DeviceLog mirrors /api/v1/device/log payload.
+type DeviceLog struct {
+	Entries []DeviceLogEntry `json:"log"`
+}
+
+type DeviceLogEntry struct {
+	ID    FlexibleInt `json:"id"`
+	Date  string      `json:"date"`
+	Log   string      `json:"log"`
+	Param string      `json:"param"`
+}
+
 // WanIPStats mirrors /api/v1/wan/ip/stats payload.
 type WanIPStats struct {
 	Wan Wan `json:"wan"`
@@ -123,6 +174,52 @@ type WanLink struct {
 	Type  string `json:"type"`
 }
 
+// WanFTTH mirrors /api/v1/wan/ftth payload.
+type WanFTTH struct {
+	Wan WanFTTHDetails `json:"wan"`
+}
+
+type WanFTTHDetails struct {
+	FTTH FTTHLine `json:"ftth"`
+}
+
+type FTTHLine struct {
+	State       string        `json:"state"`
+	Mode        string        `json:"mode"`
+	ONTState    string        `json:"ontstate"`
+	RxPower     FlexibleFloat `json:"rxpower"`
+	TxPower     FlexibleFloat `json:"txpower"`
+	Temperature FlexibleFloat `json:"temperature"`
+	Voltage     FlexibleFloat `json:"voltage"`
+	BiasCurrent FlexibleFloat `json:"biascurrent"`
+}
+
+// WanBackup mirrors /api/v1/wan/autowan payload (4G/LTE fallback).
+type WanBackup struct {
+	Wan WanBackupDetails `json:"wan"`
+}
+
+type WanBackupDetails struct {
+	Autowan WanAutowan `json:"autowan"`
+}
+
+type WanAutowan struct {
+	Enable int    `json:"enable"`
+	Active int    `json:"active"`
+	State  string `json:"state"`
+	LTE    WanLTE `json:"lte"`
+}
+
+type WanLTE struct {
+	Operator   string        `json:"operator"`
+	Technology string        `json:"technology"`
+	RSRP       FlexibleFloat `json:"rsrp"`
+	RSRQ       FlexibleFloat `json:"rsrq"`
+	SINR       FlexibleFloat `json:"sinr"`
+	RSSI       FlexibleFloat `json:"rssi"`
+	Stats      LanThroughput `json:"stats"`
+}
+
 type WanIPThroughput struct {
 	Rx WanRx `json:"rx"`
 	Tx WanTx `json:"tx"`
@@ -178,13 +275,56 @@ type LanTx struct {
 	PacketsDiscards FlexibleInt `json:"packetsdiscards"`
 }
 
+// LanPorts mirrors /api/v1/lan/ports payload.
+type LanPorts struct {
+	Lan LanPortList `json:"lan"`
+}
+
+type LanPortList struct {
+	Ports []LanPort `json:"ports"`
+}
+
+type LanPort struct {
+	ID     int           `json:"id"`
+	State  string        `json:"state"`
+	Speed  FlexibleInt   `json:"speed"`
+	Duplex string        `json:"duplex"`
+	Stats  LanThroughput `json:"stats"`
+}
+
 // WirelessStats mirrors /api/v1/wireless/{band}/stats payload.
 type WirelessStats struct {
 	Wireless Wireless `json:"wireless"`
 }
 
 type Wireless struct {
-	SSID WirelessSSID `json:"ssid"`
+	SSID WirelessSSIDList `json:"ssid"`
+}
+
+// WirelessSSIDList accepts either a single SSID object or an array of them, as radios
+// carrying a guest network report more than one SSID.
+type WirelessSSIDList []WirelessSSID
+
+func (l *WirelessSSIDList) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || string(b) == "null" {
+		*l = nil
+		return nil
+	}
+	if b[0] == '[' {
+		var list []WirelessSSID
+		if err := json.Unmarshal(b, &list); err != nil {
+			return err
+		}
+		*l = list
+		return nil
+	}
+	var single WirelessSSID
+	if err := json.Unmarshal(b, &single); err != nil {
+		return err
+	}
+	*l = WirelessSSIDList{single}
+	return nil
 }
 
 type WirelessSSID struct {
@@ -211,6 +351,482 @@ type WirelessTx struct {
 	PacketsDiscards FlexibleInt `json:"packetsdiscards"`
 }
 
+// DHCPConfig mirrors /api/v1/dhcp payload.
+type DHCPConfig struct {
+	DHCP DHCPServer `json:"dhcp"`
+}
+
+type DHCPServer struct {
+	Enable     int         `json:"enable"`
+	State      int         `json:"state"`
+	MinAddress string      `json:"minaddress"`
+	MaxAddress string      `json:"maxaddress"`
+	LeaseTime  FlexibleInt `json:"leasetime"`
+}
+
+// DHCPClients mirrors /api/v1/dhcp/clients payload (static reservations).
+type DHCPClients struct {
+	DHCP DHCPClientList `json:"dhcp"`
+}
+
+type DHCPClientList struct {
+	Clients []DHCPClient `json:"clients"`
+}
+
+type DHCPClient struct {
+	ID         int    `json:"id"`
+	Enable     int    `json:"enable"`
+	Hostname   string `json:"hostname"`
+	MacAddress string `json:"macaddress"`
+	IPAddress  string `json:"ipaddress"`
+}
+
+// Hosts mirrors /api/v1/hosts payload.
+type Hosts struct {
+	Hosts HostList `json:"hosts"`
+}
+
+type HostList struct {
+	List []Host `json:"list"`
+}
+
+type Host struct {
+	ID         int          `json:"id"`
+	Hostname   string       `json:"hostname"`
+	MacAddress string       `json:"macaddress"`
+	IPAddress  string       `json:"ipaddress"`
+	Type       string       `json:"type"`
+	Link       string       `json:"link"`
+	DeviceType string       `json:"devicetype"`
+	Active     int          `json:"active"`
+	Lease      FlexibleInt  `json:"lease"`
+	FirstSeen  string       `json:"firstseen"`
+	LastSeen   FlexibleInt  `json:"lastseen"`
+	Wireless   HostWireless `json:"wireless"`
+	Ethernet   HostEthernet `json:"ethernet"`
+	Stats      HostStats    `json:"stats"`
+	// Parental mirrors the per-host "parentalcontrol" block.
+	Parental HostParentalControl `json:"parentalcontrol"`
+}
+
+type HostParentalControl struct {
+	Enable          int         `json:"enable"`
+	Status          string      `json:"status"`
+	StatusRemaining FlexibleInt `json:"statusRemaining"`
+	StatusUntil     string      `json:"statusUntil"`
+}
+
+type HostWireless struct {
+	Band  string      `json:"band"`
+	RSSI0 FlexibleInt `json:"rssi0"`
+	RSSI1 FlexibleInt `json:"rssi1"`
+	RSSI2 FlexibleInt `json:"rssi2"`
+	MCS   FlexibleInt `json:"mcs"`
+	Rate  FlexibleInt `json:"rate"`
+}
+
+type HostStats struct {
+	Rx HostCounters `json:"rx"`
+	Tx HostCounters `json:"tx"`
+}
+
+type HostCounters struct {
+	Bytes   FlexibleInt `json:"bytes"`
+	Packets FlexibleInt `json:"packets"`
+}
+
+type HostEthernet struct {
+	PhysicalPort int         `json:"physicalport"`
+	LogicalPort  int         `json:"logicalport"`
+	Speed        FlexibleInt `json:"speed"`
+	Mode         string      `json:"mode"`
+}
+
+// XDSLInfo mirrors /api/v1/xdsl payload.
+type XDSLInfo struct {
+	Wan XDSLWan `json:"wan"`
+}
+
+type XDSLWan struct {
+	XDSL XDSLLine `json:"xdsl"`
+}
+
+type XDSLLine struct {
+	State      string        `json:"state"`
+	Modulation string        `json:"modulation"`
+	ShowTime   FlexibleInt   `json:"showtime"`
+	SyncCount  FlexibleInt   `json:"sync_count"`
+	Up         XDSLDirection `json:"up"`
+	Down       XDSLDirection `json:"down"`
+}
+
+type XDSLDirection struct {
+	Bitrates        FlexibleInt   `json:"bitrates"`
+	MaxBitrates     FlexibleInt   `json:"maxbitrates"`
+	Noise           FlexibleFloat `json:"noise"`
+	Attenuation     FlexibleFloat `json:"attenuation"`
+	Power           FlexibleFloat `json:"power"`
+	Phyr            int           `json:"phyr"`
+	Ginp            int           `json:"ginp"`
+	InterleaveDelay FlexibleInt   `json:"interleave_delay"`
+}
+
+// XDSLStats mirrors /api/v1/xdsl/stats payload.
+type XDSLStats struct {
+	Wan XDSLStatsWan `json:"wan"`
+}
+
+type XDSLStatsWan struct {
+	XDSL XDSLStatsLine `json:"xdsl"`
+}
+
+type XDSLStatsLine struct {
+	Stats XDSLErrorCounters `json:"stats"`
+}
+
+type XDSLErrorCounters struct {
+	LocalCRC  FlexibleInt `json:"local_crc"`
+	RemoteCRC FlexibleInt `json:"remote_crc"`
+	LocalFEC  FlexibleInt `json:"local_fec"`
+	RemoteFEC FlexibleInt `json:"remote_fec"`
+	LocalHEC  FlexibleInt `json:"local_hec"`
+	RemoteHEC FlexibleInt `json:"remote_hec"`
+}
+
+// VoIP mirrors /api/v1/voip payload.
+type VoIP struct {
+	Lines []VoIPLine `json:"voip"`
+}
+
+type VoIPLine struct {
+	ID            int         `json:"id"`
+	Status        string      `json:"status"`
+	CallState     string      `json:"callstate"`
+	URI           string      `json:"uri"`
+	BlockState    int         `json:"blockstate"`
+	AnonCallState int         `json:"anoncallstate"`
+	MWI           int         `json:"mwi"`
+	MessageCount  FlexibleInt `json:"message_count"`
+	NotAnswered   FlexibleInt `json:"notanswered"`
+}
+
+// CallLog mirrors /api/v1/voip/fullcalllog payload.
+type CallLog struct {
+	Calls []CallLogEntry `json:"calllog"`
+}
+
+type CallLogEntry struct {
+	ID       int         `json:"id"`
+	Number   string      `json:"number"`
+	Date     FlexibleInt `json:"date"`
+	Type     string      `json:"type"`
+	Answered int         `json:"answered"`
+	Duration FlexibleInt `json:"duree"`
+}
+
+// WirelessConfig mirrors /api/v1/wireless payload. Radios and SSIDs are keyed by band ("24", "5", ...).
+type WirelessConfig struct {
+	Wireless WirelessSettings `json:"wireless"`
+}
+
+type WirelessSettings struct {
+	Status string                        `json:"status"`
+	Radio  map[string]WirelessRadio      `json:"radio"`
+	SSID   map[string]WirelessSSIDConfig `json:"ssid"`
+}
+
+type WirelessRadio struct {
+	Enable         int         `json:"enable"`
+	State          int         `json:"state"`
+	Standard       string      `json:"standard"`
+	Channel        FlexibleInt `json:"channel"`
+	CurrentChannel FlexibleInt `json:"current_channel"`
+	DFS            int         `json:"dfs"`
+	HTBW           FlexibleInt `json:"htbw"`
+	TxPower        FlexibleInt `json:"txpower"`
+}
+
+type WirelessSSIDConfig struct {
+	ID     string `json:"id"`
+	Enable int    `json:"enable"`
+	Hidden int    `json:"hidden"`
+	BSSID  string `json:"bssid"`
+}
+
+// WirelessNeighborhood mirrors /api/v1/wireless/{band}/neighborhood payload.
+type WirelessNeighborhood struct {
+	Wireless WirelessScan `json:"wireless"`
+}
+
+type WirelessScan struct {
+	Neighborhood []WirelessNeighbor `json:"neighborhood"`
+}
+
+type WirelessNeighbor struct {
+	SSID       string      `json:"ssid"`
+	MacAddress string      `json:"macaddress"`
+	Channel    FlexibleInt `json:"channel"`
+	RSSI       FlexibleInt `json:"rssi"`
+	Mode       string      `json:"mode"`
+	Security   string      `json:"security"`
+}
+
+// NATRules mirrors /api/v1/nat/rules payload.
+type NATRules struct {
+	NAT NATRuleList `json:"nat"`
+}
+
+type NATRuleList struct {
+	Rules []NATRule `json:"rules"`
+}
+
+type NATRule struct {
+	ID           int         `json:"id"`
+	Enable       int         `json:"enable"`
+	Description  string      `json:"description"`
+	Protocol     string      `json:"protocol"`
+	ExternalIP   string      `json:"externalip"`
+	ExternalPort string      `json:"externalport"`
+	InternalIP   string      `json:"internalip"`
+	InternalPort FlexibleInt `json:"internalport"`
+}
+
+// UPnPMappings mirrors /api/v1/upnp/igd payload.
+type UPnPMappings struct {
+	UPnP UPnPIGD `json:"upnp"`
+}
+
+type UPnPIGD struct {
+	IGD UPnPIGDState `json:"igd"`
+}
+
+type UPnPIGDState struct {
+	Enable int           `json:"enable"`
+	Rules  []UPnPMapping `json:"rules"`
+}
+
+type UPnPMapping struct {
+	ID           int         `json:"id"`
+	Enable       int         `json:"enable"`
+	Status       string      `json:"status"`
+	Description  string      `json:"description"`
+	Protocol     string      `json:"protocol"`
+	ExternalPort FlexibleInt `json:"externalport"`
+	InternalIP   string      `json:"internalip"`
+	InternalPort FlexibleInt `json:"internalport"`
+	Expire       FlexibleInt `json:"expire"`
+}
+
+// FirewallRules mirrors /api/v1/firewall/rules payload.
+type FirewallRules struct {
+	Firewall FirewallRuleList `json:"firewall"`
+}
+
+type FirewallRuleList struct {
+	Rules []FirewallRule `json:"rules"`
+}
+
+// FirewallPinholes mirrors /api/v1/firewall/pinhole payload (IPv6 inbound openings).
+type FirewallPinholes struct {
+	Firewall FirewallPinholeList `json:"firewall"`
+}
+
+type FirewallPinholeList struct {
+	Pinholes []FirewallRule `json:"pinhole"`
+}
+
+type FirewallRule struct {
+	ID          int    `json:"id"`
+	Enable      int    `json:"enable"`
+	Description string `json:"description"`
+	Action      string `json:"action"`
+	SrcIP       string `json:"srcip"`
+	SrcNot      int    `json:"srcipnot"`
+	DstIP       string `json:"dstip"`
+	DstNot      int    `json:"dstipnot"`
+	SrcPorts    string `json:"srcports"`
+	DstPorts    string `json:"dstports"`
+	Order       int    `json:"order"`
+	Protocols   string `json:"protocols"`
+	IPProtocol  string `json:"ipprotocol"`
+}
+
+// IPTV mirrors /api/v1/iptv payload: one entry per multicast group joined by a decoder.
+type IPTV struct {
+	Groups []IPTVGroup `json:"iptv"`
+}
+
+type IPTVGroup struct {
+	Address     string      `json:"address"`
+	IPAddress   string      `json:"ipaddress"`
+	DestAddress string      `json:"destaddress"`
+	Receipts    FlexibleInt `json:"receipts"`
+}
+
+// WanIPTV mirrors /api/v1/wan/iptv payload (IPTV VLAN state and counters).
+type WanIPTV struct {
+	Wan WanIPTVDetails `json:"wan"`
+}
+
+type WanIPTVDetails struct {
+	IPTV WanIPTVService `json:"iptv"`
+}
+
+type WanIPTVService struct {
+	State string        `json:"state"`
+	Stats LanThroughput `json:"stats"`
+}
+
+// USB mirrors /api/v1/usb payload.
+type USB struct {
+	USB USBDevices `json:"usb"`
+}
+
+type USBDevices struct {
+	Storage []USBStorage `json:"storage"`
+	Printer []USBPrinter `json:"printer"`
+}
+
+// USBStorage sizes are reported in bytes.
+type USBStorage struct {
+	ID           int         `json:"id"`
+	Label        string      `json:"label"`
+	State        string      `json:"state"`
+	Type         string      `json:"type"`
+	Capacity     FlexibleInt `json:"capacity"`
+	Used         FlexibleInt `json:"used"`
+	Free         FlexibleInt `json:"free"`
+	Manufacturer string      `json:"manufacturer"`
+	Model        string      `json:"model"`
+}
+
+type USBPrinter struct {
+	ID           int    `json:"id"`
+	Manufacturer string `json:"manufacturer"`
+	Product      string `json:"product"`
+	State        string `json:"state"`
+}
+
+// Services mirrors /api/v1/services payload (file and media sharing).
+type Services struct {
+	Services ServiceList `json:"services"`
+}
+
+type ServiceList struct {
+	Samba   ServiceState `json:"samba"`
+	DLNA    ServiceState `json:"dlna"`
+	Printer ServiceState `json:"printer"`
+}
+
+type ServiceState struct {
+	Enable int    `json:"enable"`
+	Status string `json:"status"`
+}
+
+// DynDNS mirrors /api/v1/dyndns payload.
+type DynDNS struct {
+	DynDNS DynDNSList `json:"dyndns"`
+}
+
+type DynDNSList struct {
+	Domains []DynDNSDomain `json:"domain"`
+}
+
+type DynDNSDomain struct {
+	ID       int          `json:"id"`
+	Enable   int          `json:"enable"`
+	Server   string       `json:"server"`
+	Host     string       `json:"host"`
+	Username string       `json:"username"`
+	Record   string       `json:"record"`
+	Status   DynDNSStatus `json:"status"`
+}
+
+type DynDNSStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Date    string `json:"date"`
+	IP      string `json:"ip"`
+	Cache   string `json:"cache"`
+}
+
+// ParentalControl mirrors /api/v1/parentalcontrol payload.
+type ParentalControl struct {
+	ParentalControl ParentalControlSettings `json:"parentalcontrol"`
+}
+
+type ParentalControlSettings struct {
+	Enable        int    `json:"enable"`
+	DefaultPolicy string `json:"defaultpolicy"`
+}
+
+// ParentalScheduler mirrors /api/v1/parentalcontrol/scheduler payload.
+type ParentalScheduler struct {
+	ParentalControl ParentalSchedulerSettings `json:"parentalcontrol"`
+}
+
+type ParentalSchedulerSettings struct {
+	Scheduler Scheduler `json:"scheduler"`
+}
+
+// WirelessScheduler mirrors /api/v1/wireless/scheduler payload.
+type WirelessScheduler struct {
+	Wireless WirelessSchedulerSettings `json:"wireless"`
+}
+
+type WirelessSchedulerSettings struct {
+	Scheduler Scheduler `json:"scheduler"`
+}
+
+type Scheduler struct {
+	Enable int            `json:"enable"`
+	Rules  []ScheduleRule `json:"rules"`
+}
+
+// ScheduleRule is a weekly window, expressed in the BBox local time.
+type ScheduleRule struct {
+	ID     int          `json:"id"`
+	Enable int          `json:"enable"`
+	Start  ScheduleTime `json:"start"`
+	End    ScheduleTime `json:"end"`
+}
+
+type ScheduleTime struct {
+	Day    string `json:"day"`
+	Hour   int    `json:"hour"`
+	Minute int    `json:"minute"`
+}
+
+// Repeaters mirrors /api/v1/wireless/repeater payload (mesh extenders).
+type Repeaters struct {
+	Repeater RepeaterList `json:"repeater"`
+}
+
+type RepeaterList struct {
+	List []Repeater `json:"list"`
+}
+
+type Repeater struct {
+	ID         int            `json:"id"`
+	MacAddress string         `json:"macaddress"`
+	Name       string         `json:"name"`
+	IPAddress  string         `json:"ipaddress"`
+	Model      string         `json:"model"`
+	Firmware   string         `json:"firmware"`
+	Status     string         `json:"status"`
+	Clients    FlexibleInt    `json:"clients"`
+	Uplink     RepeaterUplink `json:"uplink"`
+}
+
+// RepeaterUplink describes the backhaul towards Parent, empty when attached to the gateway.
+type RepeaterUplink struct {
+	Type   string      `json:"type"`
+	Band   string      `json:"band"`
+	RSSI   FlexibleInt `json:"rssi"`
+	Rate   FlexibleInt `json:"rate"`
+	Parent string      `json:"parent"`
+}
+
 // FlexibleInt handles APIs that sometimes return numbers as strings.
 type FlexibleInt int64
 
@@ -230,3 +846,22 @@ func (f *FlexibleInt) UnmarshalJSON(b []byte) error {
 	*f = FlexibleInt(v)
 	return nil
 }
+
+// FlexibleFloat handles decimal values that are sometimes returned as strings.
+type FlexibleFloat float64
+
+func (f *FlexibleFloat) UnmarshalJSON(b []byte) error {
+	if len(b) > 1 && b[0] == '"' && b[len(b)-1] == '"' {
+		b = b[1 : len(b)-1]
+	}
+	if len(b) == 0 || string(b) == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+	*f = FlexibleFloat(v)
+	return nil
+}
@@ -0,0 +1,106 @@
+package bbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	maxRetryDelay         = 10 * time.Second
+	defaultLockout        = time.Minute
+)
+
+// lockoutSeconds extracts the wait hinted in the BBox "too many attempts" message.
+var lockoutSeconds = regexp.MustCompile(`(\d+)\s*(?:s\b|sec|second)`)
+
+// retry runs fn until it succeeds, fails with a non-transient error or MaxRetries is exhausted,
+// sleeping with jittered exponential backoff between attempts.
+func (c *Client) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= c.opts.MaxRetries || !transient(ctx, err) {
+			return err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// backoff returns a delay in [d/2, d] where d is backoffCeiling(attempt).
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.backoffCeiling(attempt)
+	return d/2 + rand.N(d/2+1)
+}
+
+// backoffCeiling doubles RetryBaseDelay per attempt up to maxRetryDelay.
+func (c *Client) backoffCeiling(attempt int) time.Duration {
+	d := c.opts.RetryBaseDelay
+	for i := 0; i < attempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
+
+// transient reports failures worth retrying: timeouts, resets and 5xx answers. Auth failures,
+// lockouts, other 4xx and the caller's own cancellation are final.
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var authErr *AuthError
+	var lockErr *LockoutError
+	if errors.As(err, &authErr) || errors.As(err, &lockErr) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// lockedOut recognises the BBox refusing logins after too many failed attempts.
+func lockedOut(resp *http.Response, body []byte) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return bytes.Contains(bytes.ToLower(body), []byte("too many"))
+}
+
+// lockoutDelay reads how long the BBox wants logins to stop, from Retry-After or the body.
+func lockoutDelay(resp *http.Response, body []byte) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	if m := lockoutSeconds.FindSubmatch(body); m != nil {
+		if secs, err := strconv.Atoi(string(m[1])); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return defaultLockout
+}
@@ -0,0 +1,180 @@
+package bbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTransient(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"500", context.Background(), &StatusError{StatusCode: http.StatusInternalServerError}, true},
+		{"503 wrapped", context.Background(), fmt.Errorf("fetch: %w", &StatusError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"404", context.Background(), &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"400", context.Background(), &StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"auth", context.Background(), &AuthError{StatusCode: http.StatusUnauthorized}, false},
+		{"lockout", context.Background(), &LockoutError{Until: time.Now().Add(time.Minute)}, false},
+		{"timeout", context.Background(), fmt.Errorf("request: %w", &net.DNSError{IsTimeout: true}), true},
+		{"connection reset", context.Background(), fmt.Errorf("request: %w", syscall.ECONNRESET), true},
+		{"connection refused", context.Background(), fmt.Errorf("request: %w", syscall.ECONNREFUSED), true},
+		{"unexpected EOF", context.Background(), fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"other", context.Background(), errors.New("decode failed"), false},
+		{"caller cancelled", cancelled, &StatusError{StatusCode: http.StatusBadGateway}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("transient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockedOut(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{"429", http.StatusTooManyRequests, "", true},
+		{"body hint", http.StatusUnauthorized, `{"exception":{"errors":[{"reason":"Too many attempts"}]}}`, true},
+		{"wrong password", http.StatusUnauthorized, `{"exception":{"errors":[{"reason":"password"}]}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if got := lockedOut(resp, []byte(tt.body)); got != tt.want {
+				t.Errorf("lockedOut = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		body       string
+		want       time.Duration
+	}{
+		{"retry-after", "120", "", 120 * time.Second},
+		{"retry-after wins over body", "10", "wait 30 seconds", 10 * time.Second},
+		{"body seconds", "", "Too many attempts, wait 30 seconds", 30 * time.Second},
+		{"body short unit", "", "retry in 45s", 45 * time.Second},
+		{"invalid retry-after falls back to body", "soon", "wait 5 sec", 5 * time.Second},
+		{"no hint", "", "Too many attempts", defaultLockout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := lockoutDelay(resp, []byte(tt.body)); got != tt.want {
+				t.Errorf("lockoutDelay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	c := &Client{opts: Options{RetryBaseDelay: 100 * time.Millisecond}}
+	for attempt := range 70 {
+		ceiling := maxRetryDelay
+		if attempt < 10 {
+			ceiling = min(c.opts.RetryBaseDelay<<attempt, maxRetryDelay)
+		}
+		for range 100 {
+			d := c.backoff(attempt)
+			if d < ceiling/2 || d > ceiling {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, d, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	var gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == loginRoute {
+			return
+		}
+		switch n := gets.Add(1); {
+		case r.URL.Path == "/api/v1/device/mem":
+			w.WriteHeader(http.StatusNotFound)
+		case n <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`[{}]`))
+		}
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "secret", Options{MaxRetries: 3, RetryBaseDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := c.FetchCPU(context.Background()); err != nil {
+		t.Fatalf("FetchCPU: %v", err)
+	}
+	if got := gets.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 (two 503 then success)", got)
+	}
+
+	gets.Store(10)
+	_, err = c.FetchMem(context.Background())
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("FetchMem error = %v, want 404 StatusError", err)
+	}
+	if got := gets.Load(); got != 11 {
+		t.Errorf("404 was retried: %d requests, want 1", got-10)
+	}
+}
+
+func TestLoginLockoutIsHonoured(t *testing.T) {
+	var logins atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == loginRoute {
+			logins.Add(1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "secret", Options{MaxRetries: 3, RetryBaseDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for range 3 {
+		_, err := c.FetchCPU(context.Background())
+		var lockErr *LockoutError
+		if !errors.As(err, &lockErr) {
+			t.Fatalf("error = %v, want LockoutError", err)
+		}
+		if until := time.Until(lockErr.Until); until < 50*time.Second || until > time.Minute {
+			t.Errorf("lockout ends in %v, want about 60s", until)
+		}
+	}
+	if got := logins.Load(); got != 1 {
+		t.Errorf("login attempts = %d, want 1 during the lockout", got)
+	}
+}
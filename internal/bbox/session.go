@@ -0,0 +1,127 @@
+package bbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// loginCall is a login shared by every caller that needed one at the same time.
+type loginCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Login authenticates explicitly. It is optional: requests log in on demand and keep the
+// session cookie until the BBox rejects it.
+func (c *Client) Login(ctx context.Context) error {
+	c.mu.Lock()
+	gen := c.generation
+	c.mu.Unlock()
+	return c.relogin(ctx, gen)
+}
+
+// ensureSession logs in when no session is held and returns the generation of the
+// session the caller is about to use.
+func (c *Client) ensureSession(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	gen, ok := c.generation, c.authenticated
+	c.mu.Unlock()
+	if ok {
+		return gen, nil
+	}
+
+	if err := c.relogin(ctx, gen); err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation, nil
+}
+
+// invalidate drops the session if it is still the one the caller saw rejected.
+func (c *Client) invalidate(gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == gen {
+		c.authenticated = false
+	}
+}
+
+// relogin authenticates unless a session newer than gen is already established.
+// Concurrent callers wait on a single in-flight login and share its result; each stops
+// waiting only when its own ctx is done. A rejected password is returned again without
+// contacting the BBox until LoginRetryDelay has passed.
+func (c *Client) relogin(ctx context.Context, gen uint64) error {
+	c.mu.Lock()
+	if c.authenticated && c.generation != gen {
+		c.mu.Unlock()
+		return nil
+	}
+	call := c.inflight
+	if call == nil {
+		if c.authErr != nil && time.Now().Before(c.authRetryAt) {
+			err := c.authErr
+			c.mu.Unlock()
+			return err
+		}
+		call = &loginCall{done: make(chan struct{})}
+		c.inflight = call
+		go c.runLogin(context.WithoutCancel(ctx), call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runLogin performs a shared login. It is detached from the caller that started it and
+// bounded by its own deadline, so one caller's timeout does not fail the other waiters.
+func (c *Client) runLogin(ctx context.Context, call *loginCall) {
+	ctx, cancel := context.WithTimeout(ctx, c.loginTimeout())
+	defer cancel()
+
+	call.err = c.retry(ctx, func() error { return c.login(ctx) })
+
+	c.mu.Lock()
+	var authErr *AuthError
+	switch {
+	case call.err == nil:
+		c.authenticated = true
+		c.generation++
+		c.authErr = nil
+	case errors.As(call.err, &authErr):
+		c.authErr = authErr
+		c.authRetryAt = time.Now().Add(c.opts.LoginRetryDelay)
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// loginTimeout leaves room for every login attempt and the backoff between them.
+func (c *Client) loginTimeout() time.Duration {
+	timeout := c.opts.RequestTimeout
+	for attempt := range c.opts.MaxRetries {
+		timeout += c.backoffCeiling(attempt) + c.opts.RequestTimeout
+	}
+	return timeout
+}
+
+// sessionExpired recognises a 401 or a redirect to the login page.
+func sessionExpired(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	// Request.Response is only set when the final request was produced by a redirect.
+	if resp.Request == nil || resp.Request.Response == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(resp.Request.URL.Path), "login")
+}
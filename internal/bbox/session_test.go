@@ -0,0 +1,199 @@
+package bbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSessionBox issues a numbered session cookie on login and rejects any other session.
+type fakeSessionBox struct {
+	logins    atomic.Int32
+	valid     atomic.Int32
+	loginWait time.Duration
+	// expired answers a rejected session; it defaults to 401.
+	expired func(w http.ResponseWriter, r *http.Request)
+}
+
+func (b *fakeSessionBox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case loginRoute:
+		time.Sleep(b.loginWait)
+		n := b.logins.Add(1)
+		b.valid.Store(n)
+		http.SetCookie(w, &http.Cookie{Name: "BBOX_ID", Value: strconv.Itoa(int(n)), Path: "/"})
+	case "/login.html":
+		_, _ = w.Write([]byte("<html>login</html>"))
+	default:
+		c, err := r.Cookie("BBOX_ID")
+		if err != nil || c.Value != strconv.Itoa(int(b.valid.Load())) {
+			if b.expired != nil {
+				b.expired(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[{}]`))
+	}
+}
+
+// expire makes the BBox drop the current session, as it does after its idle timeout.
+func (b *fakeSessionBox) expire() {
+	b.valid.Store(-1)
+}
+
+func newSessionClient(t *testing.T, box http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(box)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(srv.URL, "secret", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestConcurrentExpiryLogsInOnce(t *testing.T) {
+	box := &fakeSessionBox{loginWait: 20 * time.Millisecond}
+	c := newSessionClient(t, box)
+	ctx := context.Background()
+
+	if _, err := c.FetchCPU(ctx); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	box.expire()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.FetchCPU(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("fetch after expiry: %v", err)
+		}
+	}
+	if got := box.logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want 2 (initial + one shared re-login)", got)
+	}
+}
+
+func TestLoginWaiterContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var logins atomic.Int32
+	c := newSessionClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == loginRoute {
+			logins.Add(1)
+			close(started)
+			<-release
+		}
+	}))
+
+	leader := make(chan error, 1)
+	go func() { leader <- c.Login(context.Background()) }()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Login(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("waiter error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-leader; err != nil {
+		t.Errorf("leader login: %v", err)
+	}
+	if got := logins.Load(); got != 1 {
+		t.Errorf("logins = %d, want 1", got)
+	}
+}
+
+func TestLoginOutlivesLeaderDeadline(t *testing.T) {
+	box := &fakeSessionBox{loginWait: 50 * time.Millisecond}
+	c := newSessionClient(t, box)
+
+	leaderCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	leader := make(chan error, 1)
+	go func() { leader <- c.Login(leaderCtx) }()
+	for {
+		c.mu.Lock()
+		started := c.inflight != nil
+		c.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := c.Login(context.Background()); err != nil {
+		t.Errorf("waiter login after the leader's deadline: %v", err)
+	}
+	if err := <-leader; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("leader error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := box.logins.Load(); got != 1 {
+		t.Errorf("logins = %d, want 1", got)
+	}
+}
+
+func TestExpiryByRedirectToLoginPage(t *testing.T) {
+	box := &fakeSessionBox{expired: func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login.html", http.StatusFound)
+	}}
+	c := newSessionClient(t, box)
+	ctx := context.Background()
+
+	if _, err := c.FetchCPU(ctx); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	box.expire()
+	if _, err := c.FetchCPU(ctx); err != nil {
+		t.Fatalf("fetch after redirect: %v", err)
+	}
+	if got := box.logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want 2", got)
+	}
+}
+
+func TestSessionExpired(t *testing.T) {
+	redirectedTo := func(path string) *http.Request {
+		return &http.Request{URL: &url.URL{Path: path}, Response: &http.Response{StatusCode: http.StatusFound}}
+	}
+	tests := []struct {
+		name string
+		resp *http.Response
+		want bool
+	}{
+		{"ok", &http.Response{StatusCode: http.StatusOK, Request: &http.Request{URL: &url.URL{Path: "/api/v1/device"}}}, false},
+		{"unauthorized", &http.Response{StatusCode: http.StatusUnauthorized}, true},
+		{"forbidden", &http.Response{StatusCode: http.StatusForbidden}, false},
+		{"redirect to login", &http.Response{StatusCode: http.StatusOK, Request: redirectedTo("/Login.html")}, true},
+		{"redirect elsewhere", &http.Response{StatusCode: http.StatusOK, Request: redirectedTo("/api/v2/device")}, false},
+		{"login path without redirect", &http.Response{StatusCode: http.StatusOK, Request: &http.Request{URL: &url.URL{Path: "/login"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionExpired(tt.resp); got != tt.want {
+				t.Errorf("sessionExpired = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
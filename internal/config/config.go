@@ -9,10 +9,24 @@ import (
 
 // Config mirrors the existing appsettings.json fields expected by the exporter.
 type Config struct {
-	BBoxAPIURL                 string `json:"BBoxAPIURL"`
-	BBoxPassword               string `json:"BBoxPassword"`
-	BBoxAPIRefreshTime         int    `json:"BBoxAPIRefreshTime"`
-	MetricsServerListeningPort int    `json:"MetricsServerListeningPort"`
+	BBoxAPIURL                 string   `json:"BBoxAPIURL"`
+	BBoxPassword               string   `json:"BBoxPassword"`
+	BBoxAPIRefreshTime         int      `json:"BBoxAPIRefreshTime"`
+	MetricsServerListeningPort int      `json:"MetricsServerListeningPort"`
+	TopTalkersCount            int      `json:"TopTalkersCount"`
+	ForwardDeviceLog           bool     `json:"ForwardDeviceLog"`
+	WirelessScanRefreshTime    int      `json:"WirelessScanRefreshTime"`
+	BBoxAPIMaxRetries          int      `json:"BBoxAPIMaxRetries"`
+	BBoxAPIRetryBaseDelayMs    int      `json:"BBoxAPIRetryBaseDelayMs"`
+	BBoxAPICircuitThreshold    int      `json:"BBoxAPICircuitThreshold"`
+	BBoxAPICircuitOpenTime     int      `json:"BBoxAPICircuitOpenTime"`
+	BBoxAPIConcurrency         int      `json:"BBoxAPIConcurrency"`
+	BBoxAPIEndpointTimeout     int      `json:"BBoxAPIEndpointTimeout"`
+	BBoxAPIRequestTimeout      int      `json:"BBoxAPIRequestTimeout"`
+	BBoxTLSCAFile              string   `json:"BBoxTLSCAFile"`
+	BBoxTLSPinnedSHA256        []string `json:"BBoxTLSPinnedSHA256"`
+	BBoxTLSServerName          string   `json:"BBoxTLSServerName"`
+	BBoxTLSInsecureSkipVerify  bool     `json:"BBoxTLSInsecureSkipVerify"`
 }
 
 // Load reads configuration from disk and applies minimal validation/defaults.
@@ -37,12 +51,52 @@ func Load(path string) (Config, error) {
 	if cfg.BBoxPassword == "" {
 		return Config{}, fmt.Errorf("BBoxPassword is required")
 	}
+	if cfg.BBoxTLSInsecureSkipVerify && (cfg.BBoxTLSCAFile != "" || len(cfg.BBoxTLSPinnedSHA256) > 0) {
+		return Config{}, fmt.Errorf("BBoxTLSInsecureSkipVerify cannot be combined with BBoxTLSCAFile or BBoxTLSPinnedSHA256")
+	}
+	if cfg.BBoxTLSCAFile != "" && len(cfg.BBoxTLSPinnedSHA256) > 0 {
+		return Config{}, fmt.Errorf("BBoxTLSCAFile cannot be combined with BBoxTLSPinnedSHA256: the pin replaces chain verification")
+	}
 	if cfg.BBoxAPIRefreshTime <= 0 {
 		cfg.BBoxAPIRefreshTime = int((60 * time.Second).Seconds())
 	}
 	if cfg.MetricsServerListeningPort == 0 {
 		cfg.MetricsServerListeningPort = 9100
 	}
+	if cfg.WirelessScanRefreshTime < 0 {
+		cfg.WirelessScanRefreshTime = 0
+	}
+	if cfg.TopTalkersCount <= 0 {
+		cfg.TopTalkersCount = 10
+	}
+	// Zero keeps the default; a negative value disables retries.
+	if cfg.BBoxAPIMaxRetries == 0 {
+		cfg.BBoxAPIMaxRetries = 3
+	} else if cfg.BBoxAPIMaxRetries < 0 {
+		cfg.BBoxAPIMaxRetries = 0
+	}
+	if cfg.BBoxAPIRetryBaseDelayMs <= 0 {
+		cfg.BBoxAPIRetryBaseDelayMs = 500
+	}
+	if cfg.BBoxAPICircuitThreshold <= 0 {
+		cfg.BBoxAPICircuitThreshold = 5
+	}
+	if cfg.BBoxAPICircuitOpenTime <= 0 {
+		cfg.BBoxAPICircuitOpenTime = int((30 * time.Second).Seconds())
+	}
+	if cfg.BBoxAPIConcurrency <= 0 {
+		cfg.BBoxAPIConcurrency = 4
+	}
+	if cfg.BBoxAPIEndpointTimeout <= 0 {
+		cfg.BBoxAPIEndpointTimeout = int((12 * time.Second).Seconds())
+	}
+	if cfg.BBoxAPIRequestTimeout <= 0 {
+		cfg.BBoxAPIRequestTimeout = int((5 * time.Second).Seconds())
+	}
+	if cfg.BBoxAPIRequestTimeout >= cfg.BBoxAPIEndpointTimeout {
+		return Config{}, fmt.Errorf("BBoxAPIRequestTimeout (%ds) must be lower than BBoxAPIEndpointTimeout (%ds) so timed-out requests can be retried",
+			cfg.BBoxAPIRequestTimeout, cfg.BBoxAPIEndpointTimeout)
+	}
 
 	return cfg, nil
 }
@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type backupGauges struct {
+	enabled *prometheus.GaugeVec
+	active  *prometheus.GaugeVec
+	rsrp    *prometheus.GaugeVec
+	rsrq    *prometheus.GaugeVec
+	sinr    *prometheus.GaugeVec
+	rssi    *prometheus.GaugeVec
+	rxBytes *prometheus.GaugeVec
+	txBytes *prometheus.GaugeVec
+	info    *prometheus.GaugeVec
+}
+
+func newBackupGauges() backupGauges {
+	// Label-less vecs so the series disappear on boxes without a 4G key.
+	return backupGauges{
+		enabled: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_enabled", Help: "4G backup (autowan) enabled flag"}, nil),
+		active:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_active", Help: "Traffic currently routed over the 4G backup (1=active,0=inactive)"}, nil),
+		rsrp:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_signal_rsrp_dbm", Help: "LTE reference signal received power in dBm"}, nil),
+		rsrq:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_signal_rsrq_db", Help: "LTE reference signal received quality in dB"}, nil),
+		sinr:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_signal_sinr_db", Help: "LTE signal to interference plus noise ratio in dB"}, nil),
+		rssi:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_signal_rssi_dbm", Help: "LTE received signal strength in dBm"}, nil),
+		rxBytes: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_stats_rx_bytes", Help: "Bytes received over the 4G backup"}, nil),
+		txBytes: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wan_backup_stats_tx_bytes", Help: "Bytes sent over the 4G backup"}, nil),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wan_backup_info", Help: "4G backup metadata (labels hold values, gauge is always 1)"},
+			[]string{"state", "operator", "technology"},
+		),
+	}
+}
+
+func (g backupGauges) reset() {
+	for _, v := range []*prometheus.GaugeVec{g.enabled, g.active, g.rsrp, g.rsrq, g.sinr, g.rssi, g.rxBytes, g.txBytes, g.info} {
+		v.Reset()
+	}
+}
+
+// refreshBackup updates 4G fallback gauges from /api/v1/wan/autowan. Boxes without 4G
+// support answer 404 and export no backup series.
+func (e *Exporter) refreshBackup(ctx context.Context) error {
+	g := e.g.backup
+	backup, err := e.client.FetchWanBackup(ctx)
+	if unsupported(err) {
+		g.reset()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetch wan backup: %w", err)
+	}
+
+	a := backup.Wan.Autowan
+	g.enabled.WithLabelValues().Set(float64(a.Enable))
+	g.active.WithLabelValues().Set(float64(a.Active))
+	g.rsrp.WithLabelValues().Set(float64(a.LTE.RSRP))
+	g.rsrq.WithLabelValues().Set(float64(a.LTE.RSRQ))
+	g.sinr.WithLabelValues().Set(float64(a.LTE.SINR))
+	g.rssi.WithLabelValues().Set(float64(a.LTE.RSSI))
+	g.rxBytes.WithLabelValues().Set(float64(a.LTE.Stats.Rx.Bytes))
+	g.txBytes.WithLabelValues().Set(float64(a.LTE.Stats.Tx.Bytes))
+
+	g.info.Reset()
+	g.info.WithLabelValues(a.State, a.LTE.Operator, a.LTE.Technology).Set(1)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type deviceGauges struct {
+	info   *prometheus.GaugeVec
+	uptime prometheus.Gauge
+	boots  prometheus.Gauge
+	status prometheus.Gauge
+}
+
+func newDeviceGauges() deviceGauges {
+	return deviceGauges{
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_device_info", Help: "BBox identity (labels hold values, gauge is always 1)"},
+			[]string{"model", "firmware", "firmware_main", "firmware_reco", "serial", "first_use_date"},
+		),
+		uptime: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_uptime_seconds", Help: "Seconds since the BBox last booted"}),
+		boots:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_boots", Help: "Number of boots reported by the BBox"}),
+		status: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_status", Help: "BBox device status code"}),
+	}
+}
+
+// refreshDevice updates identity and uptime gauges from /api/v1/device.
+func (e *Exporter) refreshDevice(ctx context.Context) error {
+	device, err := e.client.FetchDevice(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch device: %w", err)
+	}
+
+	g := e.g.device
+	d := device.Device
+	g.info.Reset()
+	g.info.WithLabelValues(
+		d.ModelName,
+		d.Running.Version,
+		d.Main.Version,
+		d.Reco.Version,
+		d.SerialNumber,
+		d.FirstUseDate,
+	).Set(1)
+	g.uptime.Set(float64(d.Uptime))
+	g.boots.Set(float64(d.NumberOfBoots))
+	g.status.Set(float64(d.Status))
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type deviceLogMetrics struct {
+	events *prometheus.CounterVec
+	cursor prometheus.Gauge
+}
+
+func newDeviceLogMetrics() deviceLogMetrics {
+	return deviceLogMetrics{
+		events: promauto.NewCounterVec(
+			prometheus.CounterOpts{Name: "bb_device_log_events_total", Help: "BBox system log entries processed by log type"},
+			[]string{"type"},
+		),
+		cursor: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_log_cursor", Help: "ID of the last BBox log entry processed"}),
+	}
+}
+
+// forwardedLogEntry is the structured line written to stdout when log forwarding is enabled.
+type forwardedLogEntry struct {
+	Source string `json:"source"`
+	ID     int64  `json:"id"`
+	Date   string `json:"date"`
+	Type   string `json:"type"`
+	Param  string `json:"param,omitempty"`
+}
+
+// refreshDeviceLog counts new /api/v1/device/log entries once each, tracking the highest ID seen.
+// The first fetch only places the cursor on the newest entry, so entries already in the BBox
+// buffer when the exporter starts are neither counted nor forwarded again after a restart.
+func (e *Exporter) refreshDeviceLog(ctx context.Context) error {
+	deviceLog, err := e.client.FetchDeviceLog(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch device log: %w", err)
+	}
+
+	entries := deviceLog.Entries
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	if !e.logPrimed {
+		if n := len(entries); n > 0 {
+			e.setLogCursor(entries[n-1])
+		}
+		e.logPrimed = true
+		e.g.deviceLog.cursor.Set(float64(e.logCursor))
+		return nil
+	}
+
+	if logRestarted(entries, e.logCursor, e.logCursorKey) {
+		e.logCursor = 0
+	}
+
+	for _, entry := range entries {
+		if entry.ID <= e.logCursor {
+			continue
+		}
+		e.g.deviceLog.events.WithLabelValues(entry.Log).Inc()
+		if e.opts.ForwardDeviceLog {
+			e.forwardLogEntry(entry)
+		}
+		e.setLogCursor(entry)
+	}
+	e.g.deviceLog.cursor.Set(float64(e.logCursor))
+
+	return nil
+}
+
+func (e *Exporter) setLogCursor(entry bbox.DeviceLogEntry) {
+	e.logCursor = entry.ID
+	e.logCursorKey = logEntryKey(entry)
+}
+
+// logRestarted reports that IDs restarted after a reboot: the newest entry is behind the
+// cursor, or the entry now holding the cursor ID is not the one processed before.
+func logRestarted(entries []bbox.DeviceLogEntry, cursor bbox.FlexibleInt, cursorKey string) bool {
+	n := len(entries)
+	if n == 0 || cursor == 0 {
+		return false
+	}
+	if entries[n-1].ID < cursor {
+		return true
+	}
+	for _, entry := range entries {
+		if entry.ID == cursor {
+			return logEntryKey(entry) != cursorKey
+		}
+	}
+	return false
+}
+
+func logEntryKey(entry bbox.DeviceLogEntry) string {
+	return entry.Date + "|" + entry.Log + "|" + entry.Param
+}
+
+func (e *Exporter) forwardLogEntry(entry bbox.DeviceLogEntry) {
+	err := e.logOut.Encode(forwardedLogEntry{
+		Source: "bbox",
+		ID:     int64(entry.ID),
+		Date:   entry.Date,
+		Type:   entry.Log,
+		Param:  entry.Param,
+	})
+	if err != nil {
+		log.Printf("forward device log entry %d: %v", entry.ID, err)
+	}
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type dhcpGauges struct {
+	enabled            prometheus.Gauge
+	leaseTime          prometheus.Gauge
+	poolSize           prometheus.Gauge
+	leasesInUse        prometheus.Gauge
+	utilisation        prometheus.Gauge
+	staticReservations prometheus.Gauge
+}
+
+func newDHCPGauges() dhcpGauges {
+	return dhcpGauges{
+		enabled:            promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_enabled", Help: "DHCP server enabled flag"}),
+		leaseTime:          promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_lease_time_seconds", Help: "Configured DHCP lease duration"}),
+		poolSize:           promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_pool_size", Help: "Number of addresses in the DHCP pool"}),
+		leasesInUse:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_leases_in_use", Help: "Pool addresses currently leased"}),
+		utilisation:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_pool_utilisation_ratio", Help: "Leases in use divided by pool size"}),
+		staticReservations: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_dhcp_static_reservations", Help: "Enabled static DHCP reservations"}),
+	}
+}
+
+// refreshDHCP updates pool gauges from /api/v1/dhcp, /api/v1/dhcp/clients and the leases seen in
+// the /api/v1/hosts list fetched by Refresh. The DHCP endpoints do not list leases, so the
+// remaining time of each lease is only exported as bb_host_lease_remaining_seconds.
+func (e *Exporter) refreshDHCP(ctx context.Context, hosts bbox.Hosts) error {
+	cfg, err := e.client.FetchDHCP(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch dhcp: %w", err)
+	}
+	clients, err := e.client.FetchDHCPClients(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch dhcp clients: %w", err)
+	}
+
+	g := e.g.dhcp
+	g.enabled.Set(float64(cfg.DHCP.Enable))
+	g.leaseTime.Set(float64(cfg.DHCP.LeaseTime))
+
+	var reservations int
+	for _, c := range clients.DHCP.Clients {
+		if c.Enable == 1 {
+			reservations++
+		}
+	}
+	g.staticReservations.Set(float64(reservations))
+
+	first, last, err := dhcpRange(cfg.DHCP.MinAddress, cfg.DHCP.MaxAddress)
+	if err != nil {
+		return err
+	}
+	size := poolSize(first, last)
+	g.poolSize.Set(float64(size))
+
+	var inUse int
+	for _, h := range hosts.Hosts.List {
+		if h.Lease <= 0 {
+			continue
+		}
+		addr, err := netip.ParseAddr(h.IPAddress)
+		if err != nil || addr.Compare(first) < 0 || addr.Compare(last) > 0 {
+			continue
+		}
+		inUse++
+	}
+	g.leasesInUse.Set(float64(inUse))
+	if size > 0 {
+		g.utilisation.Set(float64(inUse) / float64(size))
+	} else {
+		g.utilisation.Set(0)
+	}
+
+	return nil
+}
+
+func dhcpRange(minAddr, maxAddr string) (netip.Addr, netip.Addr, error) {
+	first, err := netip.ParseAddr(minAddr)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("parse dhcp minaddress %q: %w", minAddr, err)
+	}
+	last, err := netip.ParseAddr(maxAddr)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("parse dhcp maxaddress %q: %w", maxAddr, err)
+	}
+	return first, last, nil
+}
+
+// poolSize counts the IPv4 addresses between first and last, inclusive.
+func poolSize(first, last netip.Addr) int64 {
+	if !first.Is4() || !last.Is4() || last.Less(first) {
+		return 0
+	}
+	a, b := first.As4(), last.As4()
+	lo := int64(a[0])<<24 | int64(a[1])<<16 | int64(a[2])<<8 | int64(a[3])
+	hi := int64(b[0])<<24 | int64(b[1])<<16 | int64(b[2])<<8 | int64(b[3])
+	return hi - lo + 1
+}
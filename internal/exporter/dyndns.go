@@ -0,0 +1,98 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type dyndnsGauges struct {
+	enabled      *prometheus.GaugeVec
+	status       *prometheus.GaugeVec
+	updateOK     *prometheus.GaugeVec
+	lastUpdate   *prometheus.GaugeVec
+	ipMatchesWAN *prometheus.GaugeVec
+}
+
+func newDynDNSGauges() dyndnsGauges {
+	labels := []string{"id", "provider", "hostname"}
+	return dyndnsGauges{
+		enabled: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_dyndns_enabled", Help: "DynDNS service enabled flag"}, labels),
+		status: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_dyndns_status_info", Help: "DynDNS last update status (labels hold values, gauge is always 1)"},
+			append(labels, "status", "message"),
+		),
+		updateOK:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_dyndns_last_update_success", Help: "DynDNS last update succeeded (1=success,0=failure)"}, labels),
+		lastUpdate:   promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_dyndns_last_update_timestamp_seconds", Help: "Unix time of the last DynDNS update"}, labels),
+		ipMatchesWAN: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_dyndns_ip_matches_wan", Help: "Published DynDNS IP equals the current WAN IP (1=match,0=mismatch)"}, labels),
+	}
+}
+
+// refreshDynDNS updates DynDNS client gauges from /api/v1/dyndns and compares the published
+// address with wanIP, the current WanIPInfo.Wan.IP.Address.
+func (e *Exporter) refreshDynDNS(ctx context.Context, wanIP string) error {
+	dyndns, err := e.client.FetchDynDNS(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch dyndns: %w", err)
+	}
+
+	g := e.g.dyndns
+	g.enabled.Reset()
+	g.status.Reset()
+	g.updateOK.Reset()
+	g.lastUpdate.Reset()
+	g.ipMatchesWAN.Reset()
+
+	for _, d := range dyndns.DynDNS.Domains {
+		id := strconv.Itoa(d.ID)
+		g.enabled.WithLabelValues(id, d.Server, d.Host).Set(float64(d.Enable))
+		g.status.WithLabelValues(id, d.Server, d.Host, d.Status.Status, d.Status.Message).Set(1)
+		if dyndnsSucceeded(d.Status.Status) {
+			g.updateOK.WithLabelValues(id, d.Server, d.Host).Set(1)
+		} else {
+			g.updateOK.WithLabelValues(id, d.Server, d.Host).Set(0)
+		}
+		if d.Status.Date != "" {
+			if ts, err := parseDynDNSDate(d.Status.Date); err != nil {
+				log.Printf("dyndns %s: %v", d.Host, err)
+			} else {
+				g.lastUpdate.WithLabelValues(id, d.Server, d.Host).Set(float64(ts.Unix()))
+			}
+		}
+		if d.Status.IP != "" && d.Status.IP == wanIP {
+			g.ipMatchesWAN.WithLabelValues(id, d.Server, d.Host).Set(1)
+		} else {
+			g.ipMatchesWAN.WithLabelValues(id, d.Server, d.Host).Set(0)
+		}
+	}
+
+	return nil
+}
+
+// dyndnsSucceeded recognises the update results used by the common providers.
+func dyndnsSucceeded(status string) bool {
+	switch strings.ToLower(status) {
+	case "ok", "success", "good", "nochg", "updated":
+		return true
+	}
+	return false
+}
+
+// dyndnsDateLayouts are the update date formats seen across firmwares: RFC 3339 and a numeric
+// offset without colon such as +0100.
+var dyndnsDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05-0700"}
+
+func parseDynDNSDate(v string) (time.Time, error) {
+	for _, layout := range dyndnsDateLayouts {
+		if ts, err := time.Parse(layout, v); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse last update date %q", v)
+}
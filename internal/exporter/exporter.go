@@ -2,9 +2,14 @@ package exporter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,9 +20,48 @@ import (
 
 // Exporter periodically pulls metrics from the BBox API and exposes them as Prometheus gauges.
 type Exporter struct {
-	client *bbox.Client
-	g      gauges
-	last   sampleState
+	// mu serialises Refresh calls.
+	mu sync.Mutex
+	// scanMu serialises RefreshWirelessScan, which only touches the scan gauges, so a slow
+	// scan never holds back Refresh.
+	scanMu    sync.Mutex
+	client    *bbox.Client
+	opts      Options
+	g         gauges
+	last      sampleState
+	lastHosts map[string]rateSample
+	logCursor bbox.FlexibleInt
+	// logCursorKey identifies the entry at logCursor so a reboot that reuses its ID is noticed.
+	logCursorKey string
+	// logPrimed is set once the cursor has been placed on the log present at startup.
+	logPrimed bool
+	logOut    *json.Encoder
+	// callCursor is the date of the newest call counted; callCursorKeys identifies the calls
+	// logged at that date so calls within the same second are each counted once.
+	callCursor     bbox.FlexibleInt
+	callCursorKeys map[string]bool
+	// callPrimed is set once the cursor has been placed on the call log present at startup.
+	callPrimed bool
+	// lastChannels holds the current channel per band to count channel changes.
+	lastChannels map[string]bbox.FlexibleInt
+	// lastWireless holds the previous SSID counters keyed by "band/ssid_id".
+	lastWireless map[string]rateSample
+	// lastPortStates holds the link state per Ethernet port to count flaps.
+	lastPortStates map[string]bool
+	lastIPTV       rateSample
+}
+
+// Options tunes optional collectors.
+type Options struct {
+	// TopTalkers bounds the number of hosts exported by the top talkers metric.
+	TopTalkers int
+	// ForwardDeviceLog writes each new BBox log entry to stdout as a JSON line.
+	ForwardDeviceLog bool
+	// Workers bounds the endpoints queried at the same time during Refresh.
+	Workers int
+	// EndpointTimeout bounds each endpoint (or collector) within Refresh, retries included. It
+	// must exceed the client request timeout or timed-out attempts are never retried.
+	EndpointTimeout time.Duration
 }
 
 type gauges struct {
@@ -41,20 +85,32 @@ type gauges struct {
 	lanTxBytes        prometheus.Gauge
 	lanRxMbps         prometheus.Gauge
 	lanTxMbps         prometheus.Gauge
-	wireless24RxBytes prometheus.Gauge
-	wireless24TxBytes prometheus.Gauge
-	wireless5RxBytes  prometheus.Gauge
-	wireless5TxBytes  prometheus.Gauge
 	wanRxMbps         prometheus.Gauge
 	wanTxMbps         prometheus.Gauge
-	wireless24RxMbps  prometheus.Gauge
-	wireless24TxMbps  prometheus.Gauge
-	wireless5RxMbps   prometheus.Gauge
-	wireless5TxMbps   prometheus.Gauge
 	cpuUserPct        prometheus.Gauge
 	cpuSystemPct      prometheus.Gauge
 	cpuIdlePct        prometheus.Gauge
 	cpuUsagePct       prometheus.Gauge
+	hosts             hostGauges
+	xdsl              xdslGauges
+	ftth              ftthGauges
+	voip              voipGauges
+	device            deviceGauges
+	deviceLog         deviceLogMetrics
+	wirelessRadio     wirelessRadioGauges
+	wirelessStats     wirelessStatsGauges
+	wirelessScan      wirelessScanGauges
+	lanPorts          lanPortGauges
+	dhcp              dhcpGauges
+	nat               natGauges
+	firewall          firewallGauges
+	iptv              iptvGauges
+	backup            backupGauges
+	usb               usbGauges
+	dyndns            dyndnsGauges
+	parental          parentalGauges
+	mesh              meshGauges
+	health            healthGauges
 }
 
 type sampleState struct {
@@ -63,18 +119,33 @@ type sampleState struct {
 	wanTxBytes bbox.FlexibleInt
 	lanRxBytes bbox.FlexibleInt
 	lanTxBytes bbox.FlexibleInt
-	wifi24Rx   bbox.FlexibleInt
-	wifi24Tx   bbox.FlexibleInt
-	wifi5Rx    bbox.FlexibleInt
-	wifi5Tx    bbox.FlexibleInt
 	cpuUser    int
 	cpuSystem  int
 	cpuIdle    int
 }
 
-func New(client *bbox.Client) *Exporter {
-	return &Exporter{
-		client: client,
+// rateSample keeps the previous byte counters of one host or SSID to derive throughput.
+type rateSample struct {
+	ts      time.Time
+	rxBytes bbox.FlexibleInt
+	txBytes bbox.FlexibleInt
+}
+
+func New(client *bbox.Client, opts Options) *Exporter {
+	if opts.TopTalkers <= 0 {
+		opts.TopTalkers = 10
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.EndpointTimeout <= 0 {
+		opts.EndpointTimeout = 12 * time.Second
+	}
+	e := &Exporter{
+		client:    client,
+		opts:      opts,
+		lastHosts: make(map[string]rateSample),
+		logOut:    json.NewEncoder(os.Stdout),
 		g: gauges{
 			cpuTotal:          promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_total", Help: "Total CPU time"}),
 			cpuUser:           promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_user", Help: "User CPU time"}),
@@ -113,69 +184,80 @@ func New(client *bbox.Client) *Exporter {
 					"mtu",
 				},
 			),
-			lanRxBytes:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_rx_bytes", Help: "LAN RX bytes"}),
-			lanTxBytes:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_tx_bytes", Help: "LAN TX bytes"}),
-			lanRxMbps:         promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_rx_mbps", Help: "LAN RX throughput in Mbit/s"}),
-			lanTxMbps:         promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_tx_mbps", Help: "LAN TX throughput in Mbit/s"}),
-			wireless24RxBytes: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_24_stats_rx_bytes", Help: "2.4GHz Wi-Fi RX bytes"}),
-			wireless24TxBytes: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_24_stats_tx_bytes", Help: "2.4GHz Wi-Fi TX bytes"}),
-			wireless5RxBytes:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_5_stats_rx_bytes", Help: "5GHz Wi-Fi RX bytes"}),
-			wireless5TxBytes:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_5_stats_tx_bytes", Help: "5GHz Wi-Fi TX bytes"}),
-			wanRxMbps:         promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wan_ip_stats_rx_mbps", Help: "WAN RX throughput in Mbit/s"}),
-			wanTxMbps:         promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wan_ip_stats_tx_mbps", Help: "WAN TX throughput in Mbit/s"}),
-			wireless24RxMbps:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_24_stats_rx_mbps", Help: "2.4GHz Wi-Fi RX throughput in Mbit/s"}),
-			wireless24TxMbps:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_24_stats_tx_mbps", Help: "2.4GHz Wi-Fi TX throughput in Mbit/s"}),
-			wireless5RxMbps:   promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_5_stats_rx_mbps", Help: "5GHz Wi-Fi RX throughput in Mbit/s"}),
-			wireless5TxMbps:   promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_5_stats_tx_mbps", Help: "5GHz Wi-Fi TX throughput in Mbit/s"}),
-			cpuUserPct:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_user_percent", Help: "CPU user percent"}),
-			cpuSystemPct:      promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_system_percent", Help: "CPU system percent"}),
-			cpuIdlePct:        promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_idle_percent", Help: "CPU idle percent"}),
-			cpuUsagePct:       promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_usage_percent", Help: "CPU user+system percent"}),
+			lanRxBytes:    promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_rx_bytes", Help: "LAN RX bytes"}),
+			lanTxBytes:    promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_tx_bytes", Help: "LAN TX bytes"}),
+			lanRxMbps:     promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_rx_mbps", Help: "LAN RX throughput in Mbit/s"}),
+			lanTxMbps:     promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_lan_stats_tx_mbps", Help: "LAN TX throughput in Mbit/s"}),
+			wanRxMbps:     promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wan_ip_stats_rx_mbps", Help: "WAN RX throughput in Mbit/s"}),
+			wanTxMbps:     promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wan_ip_stats_tx_mbps", Help: "WAN TX throughput in Mbit/s"}),
+			cpuUserPct:    promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_user_percent", Help: "CPU user percent"}),
+			cpuSystemPct:  promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_system_percent", Help: "CPU system percent"}),
+			cpuIdlePct:    promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_idle_percent", Help: "CPU idle percent"}),
+			cpuUsagePct:   promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_device_cpu_usage_percent", Help: "CPU user+system percent"}),
+			hosts:         newHostGauges(),
+			xdsl:          newXDSLGauges(),
+			ftth:          newFTTHGauges(),
+			voip:          newVoIPGauges(),
+			device:        newDeviceGauges(),
+			deviceLog:     newDeviceLogMetrics(),
+			wirelessRadio: newWirelessRadioGauges(),
+			wirelessStats: newWirelessStatsGauges(),
+			wirelessScan:  newWirelessScanGauges(),
+			lanPorts:      newLanPortGauges(),
+			dhcp:          newDHCPGauges(),
+			nat:           newNATGauges(),
+			firewall:      newFirewallGauges(),
+			iptv:          newIPTVGauges(),
+			backup:        newBackupGauges(),
+			usb:           newUSBGauges(),
+			dyndns:        newDynDNSGauges(),
+			parental:      newParentalGauges(),
+			mesh:          newMeshGauges(),
+			health:        newHealthGauges(client),
 		},
 	}
+	client.ObserveRequests(e.g.health.observeRequest)
+	return e
 }
 
-// Refresh performs a full login -> scrape -> logout cycle and updates gauges.
+// Refresh scrapes every endpoint and updates gauges. The client keeps its session between
+// refreshes and only logs in again when the BBox rejects it. While the client circuit is
+// open it returns at once and the previous values stay exported, marked stale.
 func (e *Exporter) Refresh(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.refresh(ctx)
+	e.g.health.setRefreshResult(err)
+	return err
+}
+
+func (e *Exporter) refresh(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if err := e.client.Login(ctx); err != nil {
-		return fmt.Errorf("login: %w", err)
+	var (
+		cpu      bbox.DeviceCPU
+		mem      bbox.DeviceMem
+		wanInfo  bbox.WanIPInfo
+		wanStats bbox.WanIPStats
+		lanStats bbox.LanStats
+		hosts    bbox.Hosts
+		hostsErr error
+	)
+	core := []task{
+		{"cpu", func(ctx context.Context) (err error) { cpu, err = e.client.FetchCPU(ctx); return err }},
+		{"mem", func(ctx context.Context) (err error) { mem, err = e.client.FetchMem(ctx); return err }},
+		{"wan info", func(ctx context.Context) (err error) { wanInfo, err = e.client.FetchWanIPInfo(ctx); return err }},
+		{"wan stats", func(ctx context.Context) (err error) { wanStats, err = e.client.FetchWanIPStats(ctx); return err }},
+		{"lan stats", func(ctx context.Context) (err error) { lanStats, err = e.client.FetchLanStats(ctx); return err }},
+		// The host list is optional and shared by several collectors, which report its error.
+		{"hosts", func(ctx context.Context) error { hosts, hostsErr = e.client.FetchHosts(ctx); return nil }},
 	}
-	defer func() {
-		if err := e.client.Logout(ctx); err != nil {
-			log.Printf("logout failed: %v", err)
+	for i, err := range runTasks(ctx, e.opts.Workers, e.opts.EndpointTimeout, core) {
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", core[i].name, err)
 		}
-	}()
-
-	cpu, err := e.client.FetchCPU(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch cpu: %w", err)
-	}
-	mem, err := e.client.FetchMem(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch mem: %w", err)
-	}
-	wanInfo, err := e.client.FetchWanIPInfo(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch wan info: %w", err)
-	}
-	wanStats, err := e.client.FetchWanIPStats(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch wan stats: %w", err)
-	}
-	lanStats, err := e.client.FetchLanStats(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch lan stats: %w", err)
-	}
-	wireless24Stats, err := e.client.FetchWireless24Stats(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch wireless 2.4 stats: %w", err)
-	}
-	wireless5Stats, err := e.client.FetchWireless5Stats(ctx)
-	if err != nil {
-		return fmt.Errorf("fetch wireless 5 stats: %w", err)
 	}
 
 	e.g.cpuTotal.Set(float64(cpu.Device.CPU.Time.Total))
@@ -237,31 +319,66 @@ func (e *Exporter) Refresh(ctx context.Context) error {
 	e.g.lanRxMbps.Set(e.throughputMbps(e.last.lanRxBytes, lanStats.Lan.Stats.Rx.Bytes, e.last.ts, now))
 	e.g.lanTxMbps.Set(e.throughputMbps(e.last.lanTxBytes, lanStats.Lan.Stats.Tx.Bytes, e.last.ts, now))
 
-	e.g.wireless24RxBytes.Set(float64(wireless24Stats.Wireless.SSID.Stats.Rx.Bytes))
-	e.g.wireless24TxBytes.Set(float64(wireless24Stats.Wireless.SSID.Stats.Tx.Bytes))
-	e.g.wireless5RxBytes.Set(float64(wireless5Stats.Wireless.SSID.Stats.Rx.Bytes))
-	e.g.wireless5TxBytes.Set(float64(wireless5Stats.Wireless.SSID.Stats.Tx.Bytes))
-	e.g.wireless24RxMbps.Set(e.throughputMbps(e.last.wifi24Rx, wireless24Stats.Wireless.SSID.Stats.Rx.Bytes, e.last.ts, now))
-	e.g.wireless24TxMbps.Set(e.throughputMbps(e.last.wifi24Tx, wireless24Stats.Wireless.SSID.Stats.Tx.Bytes, e.last.ts, now))
-	e.g.wireless5RxMbps.Set(e.throughputMbps(e.last.wifi5Rx, wireless5Stats.Wireless.SSID.Stats.Rx.Bytes, e.last.ts, now))
-	e.g.wireless5TxMbps.Set(e.throughputMbps(e.last.wifi5Tx, wireless5Stats.Wireless.SSID.Stats.Tx.Bytes, e.last.ts, now))
-
 	e.last = sampleState{
 		ts:         now,
 		wanRxBytes: wanStats.Wan.IP.Stats.Rx.Bytes,
 		wanTxBytes: wanStats.Wan.IP.Stats.Tx.Bytes,
 		lanRxBytes: lanStats.Lan.Stats.Rx.Bytes,
 		lanTxBytes: lanStats.Lan.Stats.Tx.Bytes,
-		wifi24Rx:   wireless24Stats.Wireless.SSID.Stats.Rx.Bytes,
-		wifi24Tx:   wireless24Stats.Wireless.SSID.Stats.Tx.Bytes,
-		wifi5Rx:    wireless5Stats.Wireless.SSID.Stats.Rx.Bytes,
-		wifi5Tx:    wireless5Stats.Wireless.SSID.Stats.Tx.Bytes,
 		cpuUser:    cpu.Device.CPU.Time.User,
 		cpuSystem:  cpu.Device.CPU.Time.System,
 		cpuIdle:    cpu.Device.CPU.Time.Idle,
 	}
 
-	return nil
+	withHosts := func(fn func(context.Context, bbox.Hosts) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if hostsErr != nil {
+				return fmt.Errorf("fetch hosts: %w", hostsErr)
+			}
+			return fn(ctx, hosts)
+		}
+	}
+
+	// Collectors own disjoint gauges and state, so they run side by side. Only wireless
+	// is required; the others log their failure.
+	collectors := []task{
+		{"wireless", e.refreshWireless},
+		{"hosts", withHosts(func(_ context.Context, h bbox.Hosts) error { e.setHosts(h); return nil })},
+		{"xdsl", e.refreshXDSL},
+		{"ftth", e.refreshFTTH},
+		{"voip", e.refreshVoIP},
+		{"device", e.refreshDevice},
+		{"device log", e.refreshDeviceLog},
+		{"lan ports", e.refreshLanPorts},
+		{"dhcp", withHosts(e.refreshDHCP)},
+		{"nat", e.refreshNAT},
+		{"firewall", e.refreshFirewall},
+		{"iptv", e.refreshIPTV},
+		{"wan backup", e.refreshBackup},
+		{"usb", e.refreshUSB},
+		{"dyndns", func(ctx context.Context) error { return e.refreshDynDNS(ctx, wanInfo.Wan.IP.Address) }},
+		{"parental control", withHosts(e.refreshParental)},
+		{"mesh", e.refreshMesh},
+	}
+	var wirelessErr error
+	for i, err := range runTasks(ctx, e.opts.Workers, e.opts.EndpointTimeout, collectors) {
+		switch {
+		case err == nil:
+		case collectors[i].name == "wireless":
+			wirelessErr = fmt.Errorf("refresh wireless: %w", err)
+		default:
+			log.Printf("refresh %s: %v", collectors[i].name, err)
+		}
+	}
+
+	return wirelessErr
+}
+
+// unsupported reports a 404, which the BBox returns for endpoints this firmware or line
+// type does not expose.
+func unsupported(err error) bool {
+	var statusErr *bbox.StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
 }
 
 func bytesToMegabits(v bbox.FlexibleInt) float64 {
@@ -0,0 +1,41 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+func TestRefreshWrongPasswordLogsInOncePerRefresh(t *testing.T) {
+	const loginRetryDelay = 50 * time.Millisecond
+	var logins atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			logins.Add(1)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, err := bbox.NewClient(srv.URL, "wrong", bbox.Options{LoginRetryDelay: loginRetryDelay})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	e := New(client, Options{})
+
+	for i := range 3 {
+		before := logins.Load()
+		if err := e.Refresh(context.Background()); err == nil {
+			t.Fatalf("refresh %d succeeded with a rejected password", i)
+		}
+		if got := logins.Load() - before; got != 1 {
+			t.Errorf("refresh %d sent %d logins, want 1", i, got)
+		}
+		time.Sleep(loginRetryDelay + 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type firewallGauges struct {
+	rules       *prometheus.GaugeVec
+	rulesetHash prometheus.Gauge
+	rulesetInfo *prometheus.GaugeVec
+}
+
+func newFirewallGauges() firewallGauges {
+	return firewallGauges{
+		rules: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_firewall_rules", Help: "Firewall rules by kind (rule, pinhole), action and state"},
+			[]string{"kind", "action", "state"},
+		),
+		rulesetHash: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_firewall_ruleset_hash", Help: "Numeric fingerprint of the whole firewall and pinhole ruleset; alert on changes()"}),
+		rulesetInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_firewall_ruleset_info", Help: "Firewall ruleset fingerprint (labels hold values, gauge is always 1)"},
+			[]string{"sha256"},
+		),
+	}
+}
+
+// refreshFirewall audits /api/v1/firewall/rules and /api/v1/firewall/pinhole.
+func (e *Exporter) refreshFirewall(ctx context.Context) error {
+	rules, err := e.client.FetchFirewallRules(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch firewall rules: %w", err)
+	}
+	pinholes, err := e.client.FetchFirewallPinholes(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch firewall pinholes: %w", err)
+	}
+
+	g := e.g.firewall
+	g.rules.Reset()
+	countFirewallRules(g.rules, "rule", rules.Firewall.Rules)
+	countFirewallRules(g.rules, "pinhole", pinholes.Firewall.Pinholes)
+
+	sum, err := rulesetHash(rules.Firewall.Rules, pinholes.Firewall.Pinholes)
+	if err != nil {
+		return err
+	}
+	// The leading 48 bits fit exactly in a float64 sample, enough to detect drift.
+	g.rulesetHash.Set(float64(binary.BigEndian.Uint64(append([]byte{0, 0}, sum[:6]...))))
+	g.rulesetInfo.Reset()
+	g.rulesetInfo.WithLabelValues(hex.EncodeToString(sum[:])).Set(1)
+
+	return nil
+}
+
+func countFirewallRules(vec *prometheus.GaugeVec, kind string, rules []bbox.FirewallRule) {
+	for _, r := range rules {
+		state := "disabled"
+		if r.Enable == 1 {
+			state = "enabled"
+		}
+		vec.WithLabelValues(kind, strings.ToLower(r.Action), state).Inc()
+	}
+}
+
+// rulesetHash fingerprints both rule lists independently of the order the API returns them in.
+func rulesetHash(rules, pinholes []bbox.FirewallRule) ([sha256.Size]byte, error) {
+	sorted := func(in []bbox.FirewallRule) []bbox.FirewallRule {
+		out := append([]bbox.FirewallRule(nil), in...)
+		sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+		return out
+	}
+	payload, err := json.Marshal(struct {
+		Rules    []bbox.FirewallRule `json:"rules"`
+		Pinholes []bbox.FirewallRule `json:"pinholes"`
+	}{sorted(rules), sorted(pinholes)})
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("encode firewall ruleset: %w", err)
+	}
+	return sha256.Sum256(payload), nil
+}
@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type ftthGauges struct {
+	up          *prometheus.GaugeVec
+	rxPower     *prometheus.GaugeVec
+	txPower     *prometheus.GaugeVec
+	temperature *prometheus.GaugeVec
+	voltage     *prometheus.GaugeVec
+	biasCurrent *prometheus.GaugeVec
+	info        *prometheus.GaugeVec
+}
+
+func newFTTHGauges() ftthGauges {
+	// Label-less vecs so the series disappear on boxes without an optical line.
+	return ftthGauges{
+		up:          promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_up", Help: "FTTH optical link state (1=Up,0=Down)"}, nil),
+		rxPower:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_rx_power_dbm", Help: "FTTH optical receive power in dBm"}, nil),
+		txPower:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_tx_power_dbm", Help: "FTTH optical transmit power in dBm"}, nil),
+		temperature: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_sfp_temperature_celsius", Help: "FTTH SFP/ONT temperature in Celsius"}, nil),
+		voltage:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_sfp_voltage_volts", Help: "FTTH SFP/ONT supply voltage"}, nil),
+		biasCurrent: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_ftth_sfp_bias_current_milliamperes", Help: "FTTH laser bias current in mA"}, nil),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_ftth_info", Help: "FTTH link metadata (labels hold values, gauge is always 1)"},
+			[]string{"state", "mode", "ont_state"},
+		),
+	}
+}
+
+func (g ftthGauges) reset() {
+	for _, v := range []*prometheus.GaugeVec{g.up, g.rxPower, g.txPower, g.temperature, g.voltage, g.biasCurrent, g.info} {
+		v.Reset()
+	}
+}
+
+// refreshFTTH updates optical gauges from /api/v1/wan/ftth. Boxes without an optical line
+// (xDSL) answer 404 and export no FTTH series.
+func (e *Exporter) refreshFTTH(ctx context.Context) error {
+	g := e.g.ftth
+	ftth, err := e.client.FetchWanFTTH(ctx)
+	if unsupported(err) {
+		g.reset()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetch ftth: %w", err)
+	}
+
+	line := ftth.Wan.FTTH
+	if strings.EqualFold(line.State, "up") {
+		g.up.WithLabelValues().Set(1)
+	} else {
+		g.up.WithLabelValues().Set(0)
+	}
+	g.rxPower.WithLabelValues().Set(float64(line.RxPower))
+	g.txPower.WithLabelValues().Set(float64(line.TxPower))
+	g.temperature.WithLabelValues().Set(float64(line.Temperature))
+	g.voltage.WithLabelValues().Set(float64(line.Voltage))
+	g.biasCurrent.WithLabelValues().Set(float64(line.BiasCurrent))
+
+	g.info.Reset()
+	g.info.WithLabelValues(line.State, line.Mode, line.ONTState).Set(1)
+
+	return nil
+}
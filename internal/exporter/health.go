@@ -0,0 +1,54 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type healthGauges struct {
+	circuitState    prometheus.GaugeFunc
+	dataStale       prometheus.Gauge
+	lastSuccess     prometheus.Gauge
+	requestDuration *prometheus.HistogramVec
+}
+
+func newHealthGauges(client *bbox.Client) healthGauges {
+	return healthGauges{
+		circuitState: promauto.NewGaugeFunc(
+			prometheus.GaugeOpts{Name: "bb_exporter_api_circuit_state", Help: "BBox API circuit breaker state (0=closed,1=half-open,2=open)"},
+			func() float64 { return float64(client.CircuitState()) },
+		),
+		dataStale: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "bb_exporter_data_stale",
+			Help: "Last refresh failed and BBox metrics hold values from the last successful one (1=stale,0=fresh)",
+		}),
+		lastSuccess: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_exporter_last_success_timestamp_seconds", Help: "Unix time of the last successful refresh"}),
+		requestDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "bb_exporter_api_request_duration_seconds",
+				Help:    "Duration of BBox API requests, retries and re-login included",
+				Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20},
+			},
+			[]string{"endpoint"},
+		),
+	}
+}
+
+// observeRequest feeds the request duration histogram; see bbox.Client.ObserveRequests.
+func (g healthGauges) observeRequest(route string, elapsed time.Duration) {
+	g.requestDuration.WithLabelValues(route).Observe(elapsed.Seconds())
+}
+
+// setRefreshResult marks the exported data fresh or stale after a refresh.
+func (g healthGauges) setRefreshResult(err error) {
+	if err != nil {
+		g.dataStale.Set(1)
+		return
+	}
+	g.dataStale.Set(0)
+	g.lastSuccess.SetToCurrentTime()
+}
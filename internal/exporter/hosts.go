@@ -0,0 +1,186 @@
+package exporter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type hostGauges struct {
+	active         *prometheus.GaugeVec
+	leaseRemaining *prometheus.GaugeVec
+	rssi           *prometheus.GaugeVec
+	linkRate       *prometheus.GaugeVec
+	count          *prometheus.GaugeVec
+	rxBytes        *prometheus.GaugeVec
+	txBytes        *prometheus.GaugeVec
+	rxMbps         *prometheus.GaugeVec
+	txMbps         *prometheus.GaugeVec
+	topTalker      *prometheus.GaugeVec
+}
+
+type hostRate struct {
+	mac      string
+	hostname string
+	rxMbps   float64
+	txMbps   float64
+}
+
+func newHostGauges() hostGauges {
+	return hostGauges{
+		active: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_active", Help: "Host seen by the BBox (1=active,0=inactive)"},
+			[]string{"mac", "hostname", "ip", "link", "band"},
+		),
+		leaseRemaining: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_lease_remaining_seconds", Help: "Remaining DHCP lease time per host"},
+			[]string{"mac"},
+		),
+		rssi: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_rssi_dbm", Help: "Best antenna RSSI of an active Wi-Fi host in dBm"},
+			[]string{"mac", "band"},
+		),
+		linkRate: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_link_rate_mbps", Help: "Negotiated link rate of an active host in Mbit/s"},
+			[]string{"mac", "link"},
+		),
+		count: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_hosts", Help: "Number of known hosts by state"},
+			[]string{"state"},
+		),
+		rxBytes: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_rx_bytes", Help: "Host RX bytes"},
+			[]string{"mac"},
+		),
+		txBytes: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_tx_bytes", Help: "Host TX bytes"},
+			[]string{"mac"},
+		),
+		rxMbps: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_rx_mbps", Help: "Host RX throughput in Mbit/s"},
+			[]string{"mac"},
+		),
+		txMbps: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_tx_mbps", Help: "Host TX throughput in Mbit/s"},
+			[]string{"mac"},
+		),
+		topTalker: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_host_top_talker_mbps", Help: "Throughput in Mbit/s of the busiest hosts ranked by RX+TX"},
+			[]string{"rank", "mac", "hostname", "direction"},
+		),
+	}
+}
+
+// setHosts updates the per-device gauges from the /api/v1/hosts list fetched by Refresh.
+func (e *Exporter) setHosts(hosts bbox.Hosts) {
+	g := e.g.hosts
+	g.active.Reset()
+	g.leaseRemaining.Reset()
+	g.rssi.Reset()
+	g.linkRate.Reset()
+	g.rxBytes.Reset()
+	g.txBytes.Reset()
+	g.rxMbps.Reset()
+	g.txMbps.Reset()
+
+	now := time.Now()
+	seen := make(map[string]rateSample, len(hosts.Hosts.List))
+	rates := make([]hostRate, 0, len(hosts.Hosts.List))
+
+	var active, inactive int
+	for _, h := range hosts.Hosts.List {
+		mac := strings.ToLower(h.MacAddress)
+		if mac == "" {
+			continue
+		}
+		wifi := isWirelessHost(h)
+		band := ""
+		if wifi {
+			band = h.Wireless.Band
+		}
+
+		if h.Active == 1 {
+			active++
+		} else {
+			inactive++
+		}
+		g.active.WithLabelValues(mac, h.Hostname, h.IPAddress, h.Link, band).Set(float64(h.Active))
+		g.leaseRemaining.WithLabelValues(mac).Set(float64(h.Lease))
+
+		prev := e.lastHosts[mac]
+		rate := hostRate{
+			mac:      mac,
+			hostname: h.Hostname,
+			rxMbps:   e.throughputMbps(prev.rxBytes, h.Stats.Rx.Bytes, prev.ts, now),
+			txMbps:   e.throughputMbps(prev.txBytes, h.Stats.Tx.Bytes, prev.ts, now),
+		}
+		g.rxBytes.WithLabelValues(mac).Set(float64(h.Stats.Rx.Bytes))
+		g.txBytes.WithLabelValues(mac).Set(float64(h.Stats.Tx.Bytes))
+		g.rxMbps.WithLabelValues(mac).Set(rate.rxMbps)
+		g.txMbps.WithLabelValues(mac).Set(rate.txMbps)
+		rates = append(rates, rate)
+		seen[mac] = rateSample{ts: now, rxBytes: h.Stats.Rx.Bytes, txBytes: h.Stats.Tx.Bytes}
+
+		if h.Active != 1 {
+			continue
+		}
+		if wifi {
+			if rssi, ok := bestRSSI(h.Wireless); ok {
+				g.rssi.WithLabelValues(mac, band).Set(float64(rssi))
+			}
+			g.linkRate.WithLabelValues(mac, h.Link).Set(float64(h.Wireless.Rate))
+		} else if h.Ethernet.Speed > 0 {
+			g.linkRate.WithLabelValues(mac, h.Link).Set(float64(h.Ethernet.Speed))
+		}
+	}
+
+	g.count.WithLabelValues("active").Set(float64(active))
+	g.count.WithLabelValues("inactive").Set(float64(inactive))
+
+	e.setTopTalkers(rates)
+	// Hosts that vanished from the list are dropped so the map stays bounded.
+	e.lastHosts = seen
+}
+
+// setTopTalkers exports only the N busiest hosts to keep label cardinality bounded.
+func (e *Exporter) setTopTalkers(rates []hostRate) {
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].rxMbps+rates[i].txMbps > rates[j].rxMbps+rates[j].txMbps
+	})
+	if len(rates) > e.opts.TopTalkers {
+		rates = rates[:e.opts.TopTalkers]
+	}
+
+	e.g.hosts.topTalker.Reset()
+	for i, r := range rates {
+		rank := strconv.Itoa(i + 1)
+		e.g.hosts.topTalker.WithLabelValues(rank, r.mac, r.hostname, "rx").Set(r.rxMbps)
+		e.g.hosts.topTalker.WithLabelValues(rank, r.mac, r.hostname, "tx").Set(r.txMbps)
+	}
+}
+
+func isWirelessHost(h bbox.Host) bool {
+	return h.Wireless.Band != "" || strings.HasPrefix(strings.ToLower(h.Link), "wifi")
+}
+
+// bestRSSI returns the strongest non-zero antenna reading; unused chains report 0.
+func bestRSSI(w bbox.HostWireless) (bbox.FlexibleInt, bool) {
+	var best bbox.FlexibleInt
+	found := false
+	for _, v := range []bbox.FlexibleInt{w.RSSI0, w.RSSI1, w.RSSI2} {
+		if v == 0 {
+			continue
+		}
+		if !found || v > best {
+			best = v
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type iptvGauges struct {
+	decoders  prometheus.Gauge
+	groups    prometheus.Gauge
+	groupInfo *prometheus.GaugeVec
+	up        *prometheus.GaugeVec
+	rxBytes   *prometheus.GaugeVec
+	txBytes   *prometheus.GaugeVec
+	rxMbps    *prometheus.GaugeVec
+	txMbps    *prometheus.GaugeVec
+}
+
+func newIPTVGauges() iptvGauges {
+	return iptvGauges{
+		decoders: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_iptv_active_decoders", Help: "Decoders with at least one multicast group joined"}),
+		groups:   promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_iptv_multicast_groups", Help: "Distinct multicast groups joined"}),
+		groupInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_iptv_multicast_group_joins", Help: "Multicast group joined by a decoder (value is the receipts counter)"},
+			[]string{"group", "decoder"},
+		),
+		// Label-less vecs so the series disappear when /api/v1/wan/iptv is not exposed.
+		up:      promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_iptv_up", Help: "IPTV service state (1=Up,0=Down)"}, nil),
+		rxBytes: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_iptv_stats_rx_bytes", Help: "IPTV VLAN RX bytes"}, nil),
+		txBytes: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_iptv_stats_tx_bytes", Help: "IPTV VLAN TX bytes"}, nil),
+		rxMbps:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_iptv_stats_rx_mbps", Help: "IPTV VLAN RX throughput in Mbit/s"}, nil),
+		txMbps:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_iptv_stats_tx_mbps", Help: "IPTV VLAN TX throughput in Mbit/s"}, nil),
+	}
+}
+
+// refreshIPTV updates multicast gauges from /api/v1/iptv, then service state and VLAN
+// throughput from /api/v1/wan/iptv, which not every firmware exposes.
+func (e *Exporter) refreshIPTV(ctx context.Context) error {
+	iptv, err := e.client.FetchIPTV(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch iptv: %w", err)
+	}
+
+	g := e.g.iptv
+	decoders := make(map[string]struct{})
+	groups := make(map[string]struct{})
+	g.groupInfo.Reset()
+	for _, grp := range iptv.Groups {
+		decoders[grp.IPAddress] = struct{}{}
+		groups[grp.Address] = struct{}{}
+		g.groupInfo.WithLabelValues(grp.Address, grp.IPAddress).Set(float64(grp.Receipts))
+	}
+	g.decoders.Set(float64(len(decoders)))
+	g.groups.Set(float64(len(groups)))
+
+	wan, err := e.client.FetchWanIPTV(ctx)
+	if unsupported(err) {
+		for _, v := range []*prometheus.GaugeVec{g.up, g.rxBytes, g.txBytes, g.rxMbps, g.txMbps} {
+			v.Reset()
+		}
+		e.lastIPTV = rateSample{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetch wan iptv: %w", err)
+	}
+	svc := wan.Wan.IPTV
+	if strings.EqualFold(svc.State, "up") {
+		g.up.WithLabelValues().Set(1)
+	} else {
+		g.up.WithLabelValues().Set(0)
+	}
+
+	now := time.Now()
+	prev := e.lastIPTV
+	g.rxBytes.WithLabelValues().Set(float64(svc.Stats.Rx.Bytes))
+	g.txBytes.WithLabelValues().Set(float64(svc.Stats.Tx.Bytes))
+	g.rxMbps.WithLabelValues().Set(e.throughputMbps(prev.rxBytes, svc.Stats.Rx.Bytes, prev.ts, now))
+	g.txMbps.WithLabelValues().Set(e.throughputMbps(prev.txBytes, svc.Stats.Tx.Bytes, prev.ts, now))
+	e.lastIPTV = rateSample{ts: now, rxBytes: svc.Stats.Rx.Bytes, txBytes: svc.Stats.Tx.Bytes}
+
+	return nil
+}
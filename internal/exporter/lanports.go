@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type lanPortGauges struct {
+	up         *prometheus.GaugeVec
+	speed      *prometheus.GaugeVec
+	fullDuplex *prometheus.GaugeVec
+	rxBytes    *prometheus.GaugeVec
+	txBytes    *prometheus.GaugeVec
+	rxErrors   *prometheus.GaugeVec
+	txErrors   *prometheus.GaugeVec
+	linkFlaps  *prometheus.CounterVec
+}
+
+func newLanPortGauges() lanPortGauges {
+	labels := []string{"port"}
+	return lanPortGauges{
+		up:         promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_up", Help: "Ethernet port link state (1=Up,0=Down)"}, labels),
+		speed:      promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_speed_mbps", Help: "Ethernet port negotiated speed in Mbit/s"}, labels),
+		fullDuplex: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_full_duplex", Help: "Ethernet port duplex (1=full,0=half)"}, labels),
+		rxBytes:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_rx_bytes", Help: "Ethernet port RX bytes"}, labels),
+		txBytes:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_tx_bytes", Help: "Ethernet port TX bytes"}, labels),
+		rxErrors:   promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_rx_packets_errors", Help: "Ethernet port RX packet errors"}, labels),
+		txErrors:   promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_lan_port_tx_packets_errors", Help: "Ethernet port TX packet errors"}, labels),
+		linkFlaps: promauto.NewCounterVec(
+			prometheus.CounterOpts{Name: "bb_lan_port_link_flaps_total", Help: "Ethernet port link state transitions observed between refreshes"},
+			labels,
+		),
+	}
+}
+
+// refreshLanPorts updates per-port switch gauges from /api/v1/lan/ports and counts link flaps.
+func (e *Exporter) refreshLanPorts(ctx context.Context) error {
+	ports, err := e.client.FetchLanPorts(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch lan ports: %w", err)
+	}
+
+	g := e.g.lanPorts
+	states := make(map[string]bool, len(ports.Lan.Ports))
+	for _, p := range ports.Lan.Ports {
+		port := strconv.Itoa(p.ID)
+		up := strings.EqualFold(p.State, "up")
+
+		if up {
+			g.up.WithLabelValues(port).Set(1)
+		} else {
+			g.up.WithLabelValues(port).Set(0)
+		}
+		g.speed.WithLabelValues(port).Set(float64(p.Speed))
+		if strings.EqualFold(p.Duplex, "full") {
+			g.fullDuplex.WithLabelValues(port).Set(1)
+		} else {
+			g.fullDuplex.WithLabelValues(port).Set(0)
+		}
+		g.rxBytes.WithLabelValues(port).Set(float64(p.Stats.Rx.Bytes))
+		g.txBytes.WithLabelValues(port).Set(float64(p.Stats.Tx.Bytes))
+		g.rxErrors.WithLabelValues(port).Set(float64(p.Stats.Rx.PacketsErrors))
+		g.txErrors.WithLabelValues(port).Set(float64(p.Stats.Tx.PacketsErrors))
+
+		if prev, ok := e.lastPortStates[port]; ok && prev != up {
+			g.linkFlaps.WithLabelValues(port).Inc()
+		}
+		states[port] = up
+	}
+	e.lastPortStates = states
+
+	return nil
+}
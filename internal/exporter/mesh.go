@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// meshGatewayID is the node graph id of the BBox itself.
+const meshGatewayID = "gateway"
+
+type meshGauges struct {
+	up           *prometheus.GaugeVec
+	backhaulRSSI *prometheus.GaugeVec
+	backhaulRate *prometheus.GaugeVec
+	clients      *prometheus.GaugeVec
+	info         *prometheus.GaugeVec
+	node         *prometheus.GaugeVec
+	edge         *prometheus.GaugeVec
+}
+
+func newMeshGauges() meshGauges {
+	repeater := []string{"mac", "name"}
+	return meshGauges{
+		up: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_mesh_repeater_up", Help: "Repeater state (1=Up,0=other)"}, repeater),
+		backhaulRSSI: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_mesh_repeater_backhaul_rssi_dbm", Help: "Repeater Wi-Fi backhaul RSSI in dBm"},
+			[]string{"mac", "name", "band"},
+		),
+		backhaulRate: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_mesh_repeater_backhaul_rate_mbps", Help: "Repeater backhaul link rate in Mbit/s"},
+			[]string{"mac", "name", "band"},
+		),
+		clients: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_mesh_repeater_clients", Help: "Clients attached to the repeater"}, repeater),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_mesh_repeater_info", Help: "Repeater metadata (labels hold values, gauge is always 1)"},
+			[]string{"mac", "name", "ip", "model", "firmware", "uplink_type", "uplink_band"},
+		),
+		node: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_mesh_node_info", Help: "Mesh node for Grafana node graph (value is the repeater client count, 0 for the gateway)"},
+			[]string{"id", "title", "subtitle"},
+		),
+		edge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_mesh_edge_info", Help: "Mesh backhaul link for Grafana node graph (value is the link rate in Mbit/s)"},
+			[]string{"id", "source", "target", "type", "band"},
+		),
+	}
+}
+
+// refreshMesh updates repeater and topology gauges from /api/v1/wireless/repeater.
+func (e *Exporter) refreshMesh(ctx context.Context) error {
+	repeaters, err := e.client.FetchRepeaters(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch repeaters: %w", err)
+	}
+
+	g := e.g.mesh
+	g.up.Reset()
+	g.backhaulRSSI.Reset()
+	g.backhaulRate.Reset()
+	g.clients.Reset()
+	g.info.Reset()
+	g.node.Reset()
+	g.edge.Reset()
+
+	g.node.WithLabelValues(meshGatewayID, "BBox", "Gateway").Set(0)
+	for _, r := range repeaters.Repeater.List {
+		mac := strings.ToLower(r.MacAddress)
+		up := r.Uplink
+
+		if strings.EqualFold(r.Status, "up") {
+			g.up.WithLabelValues(mac, r.Name).Set(1)
+		} else {
+			g.up.WithLabelValues(mac, r.Name).Set(0)
+		}
+		if up.RSSI != 0 {
+			g.backhaulRSSI.WithLabelValues(mac, r.Name, up.Band).Set(float64(up.RSSI))
+		}
+		g.backhaulRate.WithLabelValues(mac, r.Name, up.Band).Set(float64(up.Rate))
+		g.clients.WithLabelValues(mac, r.Name).Set(float64(r.Clients))
+		g.info.WithLabelValues(mac, r.Name, r.IPAddress, r.Model, r.Firmware, up.Type, up.Band).Set(1)
+
+		parent := strings.ToLower(up.Parent)
+		if parent == "" {
+			parent = meshGatewayID
+		}
+		g.node.WithLabelValues(mac, r.Name, r.Model).Set(float64(r.Clients))
+		g.edge.WithLabelValues(parent+"-"+mac, parent, mac, up.Type, up.Band).Set(float64(up.Rate))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type natGauges struct {
+	ruleInfo        *prometheus.GaugeVec
+	upnpEnabled     prometheus.Gauge
+	upnpMappingInfo *prometheus.GaugeVec
+	upnpPerHost     *prometheus.GaugeVec
+}
+
+func newNATGauges() natGauges {
+	return natGauges{
+		ruleInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_nat_rule_info", Help: "Configured port-forwarding rule (1=enabled,0=disabled)"},
+			[]string{"id", "description", "protocol", "external_port", "internal_ip", "internal_port"},
+		),
+		upnpEnabled: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_upnp_enabled", Help: "UPnP IGD service enabled flag"}),
+		upnpMappingInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_upnp_mapping_info", Help: "Dynamic UPnP IGD mapping created by a LAN device (1=enabled,0=disabled)"},
+			[]string{"description", "protocol", "external_port", "internal_ip", "internal_port"},
+		),
+		upnpPerHost: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_upnp_mappings", Help: "UPnP IGD mappings per internal host"},
+			[]string{"internal_ip"},
+		),
+	}
+}
+
+// refreshNAT updates port-forwarding and UPnP inventories from /api/v1/nat/rules and /api/v1/upnp/igd.
+func (e *Exporter) refreshNAT(ctx context.Context) error {
+	rules, err := e.client.FetchNATRules(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch nat rules: %w", err)
+	}
+	upnp, err := e.client.FetchUPnPMappings(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch upnp mappings: %w", err)
+	}
+
+	g := e.g.nat
+	g.ruleInfo.Reset()
+	for _, r := range rules.NAT.Rules {
+		g.ruleInfo.WithLabelValues(
+			strconv.Itoa(r.ID),
+			r.Description,
+			strings.ToLower(r.Protocol),
+			r.ExternalPort,
+			r.InternalIP,
+			strconv.FormatInt(int64(r.InternalPort), 10),
+		).Set(float64(r.Enable))
+	}
+
+	g.upnpEnabled.Set(float64(upnp.UPnP.IGD.Enable))
+	g.upnpMappingInfo.Reset()
+	g.upnpPerHost.Reset()
+	perHost := make(map[string]int)
+	for _, m := range upnp.UPnP.IGD.Rules {
+		g.upnpMappingInfo.WithLabelValues(
+			m.Description,
+			strings.ToLower(m.Protocol),
+			strconv.FormatInt(int64(m.ExternalPort), 10),
+			m.InternalIP,
+			strconv.FormatInt(int64(m.InternalPort), 10),
+		).Set(float64(m.Enable))
+		perHost[m.InternalIP]++
+	}
+	for ip, n := range perHost {
+		g.upnpPerHost.WithLabelValues(ip).Set(float64(n))
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+const secondsPerWeek = 7 * 24 * 60 * 60
+
+type parentalGauges struct {
+	enabled          prometheus.Gauge
+	deviceBlocked    *prometheus.GaugeVec
+	blockRemaining   *prometheus.GaugeVec
+	schedulerEnabled *prometheus.GaugeVec
+	ruleActive       *prometheus.GaugeVec
+	ruleNextChange   *prometheus.GaugeVec
+}
+
+func newParentalGauges() parentalGauges {
+	rule := []string{"scheduler", "id"}
+	return parentalGauges{
+		enabled: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_parental_control_enabled", Help: "Parental control enabled flag"}),
+		deviceBlocked: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_parental_device_blocked", Help: "Host currently denied access by parental control (1=blocked,0=allowed)"},
+			[]string{"mac", "hostname"},
+		),
+		blockRemaining: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_parental_device_status_remaining_seconds", Help: "Seconds until the host parental control status changes"},
+			[]string{"mac", "hostname"},
+		),
+		schedulerEnabled: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_schedule_enabled", Help: "Scheduler enabled flag (parental, wifi)"},
+			[]string{"scheduler"},
+		),
+		ruleActive: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_schedule_rule_active", Help: "Schedule rule window currently in effect (1=inside,0=outside)"},
+			rule,
+		),
+		ruleNextChange: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_schedule_rule_next_change_timestamp_seconds", Help: "Unix time at which the schedule rule next starts or ends"},
+			rule,
+		),
+	}
+}
+
+// refreshParental updates parental-control and Wi-Fi scheduler gauges. Per-host block status
+// comes from the /api/v1/hosts list fetched by Refresh; schedule windows are evaluated in the
+// exporter's local time zone.
+func (e *Exporter) refreshParental(ctx context.Context, hosts bbox.Hosts) error {
+	pc, err := e.client.FetchParentalControl(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch parental control: %w", err)
+	}
+	pcScheduler, err := e.client.FetchParentalScheduler(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch parental scheduler: %w", err)
+	}
+	wifiScheduler, err := e.client.FetchWirelessScheduler(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch wireless scheduler: %w", err)
+	}
+
+	g := e.g.parental
+	g.enabled.Set(float64(pc.ParentalControl.Enable))
+
+	g.deviceBlocked.Reset()
+	g.blockRemaining.Reset()
+	for _, h := range hosts.Hosts.List {
+		if h.Parental.Enable != 1 {
+			continue
+		}
+		mac := strings.ToLower(h.MacAddress)
+		if strings.EqualFold(h.Parental.Status, "denied") || strings.EqualFold(h.Parental.Status, "blocked") {
+			g.deviceBlocked.WithLabelValues(mac, h.Hostname).Set(1)
+		} else {
+			g.deviceBlocked.WithLabelValues(mac, h.Hostname).Set(0)
+		}
+		g.blockRemaining.WithLabelValues(mac, h.Hostname).Set(float64(h.Parental.StatusRemaining))
+	}
+
+	g.schedulerEnabled.Reset()
+	g.ruleActive.Reset()
+	g.ruleNextChange.Reset()
+	now := time.Now()
+	e.setSchedule("parental", pcScheduler.ParentalControl.Scheduler, now)
+	e.setSchedule("wifi", wifiScheduler.Wireless.Scheduler, now)
+
+	return nil
+}
+
+func (e *Exporter) setSchedule(name string, s bbox.Scheduler, now time.Time) {
+	g := e.g.parental
+	g.schedulerEnabled.WithLabelValues(name).Set(float64(s.Enable))
+	for _, r := range s.Rules {
+		if r.Enable != 1 {
+			continue
+		}
+		start, okStart := weekSecond(r.Start)
+		end, okEnd := weekSecond(r.End)
+		if !okStart || !okEnd {
+			continue
+		}
+		id := strconv.Itoa(r.ID)
+		if scheduleActive(start, end, nowWeekSecond(now)) {
+			g.ruleActive.WithLabelValues(name, id).Set(1)
+		} else {
+			g.ruleActive.WithLabelValues(name, id).Set(0)
+		}
+		next := min(untilWeekSecond(start, now), untilWeekSecond(end, now))
+		g.ruleNextChange.WithLabelValues(name, id).Set(float64(now.Unix() + int64(next)))
+	}
+}
+
+// weekSecond converts a weekly schedule point to seconds since Sunday 00:00.
+func weekSecond(t bbox.ScheduleTime) (int, bool) {
+	day, ok := parseWeekday(t.Day)
+	if !ok {
+		return 0, false
+	}
+	return int(day)*86400 + t.Hour*3600 + t.Minute*60, true
+}
+
+func nowWeekSecond(now time.Time) int {
+	return int(now.Weekday())*86400 + now.Hour()*3600 + now.Minute()*60 + now.Second()
+}
+
+// scheduleActive reports whether now lies in [start,end), handling windows that wrap past Saturday.
+func scheduleActive(start, end, now int) bool {
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// untilWeekSecond returns the seconds until the next occurrence of the weekly point.
+func untilWeekSecond(point int, now time.Time) int {
+	d := (point - nowWeekSecond(now)) % secondsPerWeek
+	if d <= 0 {
+		d += secondsPerWeek
+	}
+	return d
+}
+
+func parseWeekday(day string) (time.Weekday, bool) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if n, err := strconv.Atoi(day); err == nil && n >= 0 && n <= 6 {
+		return time.Weekday(n), true
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if day == name || (len(day) == 3 && strings.HasPrefix(name, day)) {
+			return d, true
+		}
+	}
+	return 0, false
+}
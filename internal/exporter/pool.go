@@ -0,0 +1,34 @@
+package exporter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// task is one independent piece of Refresh work, usually a single endpoint.
+type task struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runTasks runs tasks on at most workers goroutines, each under its own timeout, and returns
+// their errors in task order.
+func runTasks(ctx context.Context, workers int, timeout time.Duration, tasks []task) []error {
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, t := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			taskCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			errs[i] = t.run(taskCtx)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,96 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type usbGauges struct {
+	devices      *prometheus.GaugeVec
+	storageInfo  *prometheus.GaugeVec
+	capacity     *prometheus.GaugeVec
+	used         *prometheus.GaugeVec
+	free         *prometheus.GaugeVec
+	printerInfo  *prometheus.GaugeVec
+	shareEnabled *prometheus.GaugeVec
+	shareUp      *prometheus.GaugeVec
+}
+
+func newUSBGauges() usbGauges {
+	storage := []string{"id", "label", "fstype"}
+	share := []string{"service"}
+	return usbGauges{
+		devices: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_usb_devices", Help: "USB devices attached to the BBox by type"},
+			[]string{"type"},
+		),
+		storageInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_usb_storage_info", Help: "USB storage metadata (labels hold values, gauge is always 1)"},
+			[]string{"id", "label", "fstype", "state", "manufacturer", "model"},
+		),
+		capacity: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_usb_storage_capacity_bytes", Help: "USB storage capacity"}, storage),
+		used:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_usb_storage_used_bytes", Help: "USB storage used space"}, storage),
+		free:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_usb_storage_free_bytes", Help: "USB storage free space"}, storage),
+		printerInfo: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_usb_printer_info", Help: "USB printer metadata (labels hold values, gauge is always 1)"},
+			[]string{"id", "manufacturer", "product", "state"},
+		),
+		shareEnabled: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_usb_share_enabled", Help: "Sharing service enabled flag (samba, dlna, printer)"}, share),
+		shareUp:      promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_usb_share_up", Help: "Sharing service state (1=Up,0=other)"}, share),
+	}
+}
+
+// refreshUSB updates attached device, storage and share gauges from /api/v1/usb and /api/v1/services.
+func (e *Exporter) refreshUSB(ctx context.Context) error {
+	usb, err := e.client.FetchUSB(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch usb: %w", err)
+	}
+	services, err := e.client.FetchServices(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch services: %w", err)
+	}
+
+	g := e.g.usb
+	g.devices.WithLabelValues("storage").Set(float64(len(usb.USB.Storage)))
+	g.devices.WithLabelValues("printer").Set(float64(len(usb.USB.Printer)))
+
+	g.storageInfo.Reset()
+	g.capacity.Reset()
+	g.used.Reset()
+	g.free.Reset()
+	for _, s := range usb.USB.Storage {
+		id := strconv.Itoa(s.ID)
+		g.storageInfo.WithLabelValues(id, s.Label, s.Type, s.State, s.Manufacturer, s.Model).Set(1)
+		g.capacity.WithLabelValues(id, s.Label, s.Type).Set(float64(s.Capacity))
+		g.used.WithLabelValues(id, s.Label, s.Type).Set(float64(s.Used))
+		g.free.WithLabelValues(id, s.Label, s.Type).Set(float64(s.Free))
+	}
+
+	g.printerInfo.Reset()
+	for _, p := range usb.USB.Printer {
+		g.printerInfo.WithLabelValues(strconv.Itoa(p.ID), p.Manufacturer, p.Product, p.State).Set(1)
+	}
+
+	for name, svc := range map[string]bbox.ServiceState{
+		"samba":   services.Services.Samba,
+		"dlna":    services.Services.DLNA,
+		"printer": services.Services.Printer,
+	} {
+		g.shareEnabled.WithLabelValues(name).Set(float64(svc.Enable))
+		if strings.EqualFold(svc.Status, "up") {
+			g.shareUp.WithLabelValues(name).Set(1)
+		} else {
+			g.shareUp.WithLabelValues(name).Set(0)
+		}
+	}
+
+	return nil
+}
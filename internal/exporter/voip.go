@@ -0,0 +1,150 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type voipGauges struct {
+	registered   *prometheus.GaugeVec
+	info         *prometheus.GaugeVec
+	notAnswered  *prometheus.GaugeVec
+	messages     *prometheus.GaugeVec
+	entries      *prometheus.GaugeVec
+	calls        *prometheus.CounterVec
+	lastCallTime prometheus.Gauge
+}
+
+func newVoIPGauges() voipGauges {
+	return voipGauges{
+		registered: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_voip_line_registered", Help: "VoIP line SIP registration (1=Up,0=other)"},
+			[]string{"line"},
+		),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_voip_line_info", Help: "VoIP line metadata (labels hold values, gauge is always 1)"},
+			[]string{"line", "status", "call_state", "uri_user", "uri_domain"},
+		),
+		notAnswered: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_voip_line_not_answered_calls", Help: "Unanswered calls reported by the VoIP line"},
+			[]string{"line"},
+		),
+		messages: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_voip_line_voicemail_messages", Help: "Voicemail messages waiting on the VoIP line"},
+			[]string{"line"},
+		),
+		entries: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_voip_calllog_entries", Help: "Calls currently held in the BBox call log by type (missed, answered, outgoing)"},
+			[]string{"type"},
+		),
+		calls: promauto.NewCounterVec(
+			prometheus.CounterOpts{Name: "bb_voip_calls_total", Help: "Calls seen in the BBox call log by type (missed, answered, outgoing)"},
+			[]string{"type"},
+		),
+		lastCallTime: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_voip_last_call_timestamp_seconds", Help: "Unix time of the most recent call in the call log"}),
+	}
+}
+
+// refreshVoIP updates line status and call log metrics from /api/v1/voip and /api/v1/voip/fullcalllog.
+func (e *Exporter) refreshVoIP(ctx context.Context) error {
+	voip, err := e.client.FetchVoIP(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch voip: %w", err)
+	}
+	calls, err := e.client.FetchCallLog(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch call log: %w", err)
+	}
+
+	g := e.g.voip
+	g.registered.Reset()
+	g.info.Reset()
+	g.notAnswered.Reset()
+	g.messages.Reset()
+
+	for _, l := range voip.Lines {
+		line := strconv.Itoa(l.ID)
+		if strings.EqualFold(l.Status, "up") {
+			g.registered.WithLabelValues(line).Set(1)
+		} else {
+			g.registered.WithLabelValues(line).Set(0)
+		}
+		user, domain := splitSIPURI(l.URI)
+		g.info.WithLabelValues(line, l.Status, l.CallState, user, domain).Set(1)
+		g.notAnswered.WithLabelValues(line).Set(float64(l.NotAnswered))
+		g.messages.WithLabelValues(line).Set(float64(l.MessageCount))
+	}
+
+	entries := map[string]int{"missed": 0, "answered": 0, "outgoing": 0}
+	var last bbox.FlexibleInt
+	for _, c := range calls.Calls {
+		entries[callType(c)]++
+		last = max(last, c.Date)
+	}
+	for typ, n := range entries {
+		g.entries.WithLabelValues(typ).Set(float64(n))
+	}
+	g.lastCallTime.Set(float64(last))
+	e.countCalls(calls.Calls)
+
+	return nil
+}
+
+// countCalls counts calls newer than the cursor once each. The BBox renumbers its call log as
+// it rotates, so the cursor is the call date rather than the ID. The first fetch only places
+// the cursor, so calls already logged when the exporter starts are not counted.
+func (e *Exporter) countCalls(calls []bbox.CallLogEntry) {
+	var newest bbox.FlexibleInt
+	for _, c := range calls {
+		newest = max(newest, c.Date)
+	}
+	keys := make(map[string]bool)
+	for _, c := range calls {
+		if c.Date == newest {
+			keys[callKey(c)] = true
+		}
+		if !e.callPrimed || c.Date < e.callCursor || c.Date == e.callCursor && e.callCursorKeys[callKey(c)] {
+			continue
+		}
+		e.g.voip.calls.WithLabelValues(callType(c)).Inc()
+	}
+	if newest >= e.callCursor {
+		if newest == e.callCursor {
+			maps.Copy(keys, e.callCursorKeys)
+		}
+		e.callCursor = newest
+		e.callCursorKeys = keys
+	}
+	e.callPrimed = true
+}
+
+// callType classifies a call log entry as missed, answered or outgoing.
+func callType(c bbox.CallLogEntry) string {
+	switch {
+	case strings.EqualFold(c.Type, "out"):
+		return "outgoing"
+	case c.Answered == 1:
+		return "answered"
+	default:
+		return "missed"
+	}
+}
+
+func callKey(c bbox.CallLogEntry) string {
+	return c.Number + "|" + c.Type + "|" + strconv.Itoa(c.Answered)
+}
+
+// splitSIPURI separates "user@domain" with an optional "sip:" scheme.
+func splitSIPURI(uri string) (string, string) {
+	uri = strings.TrimPrefix(uri, "sip:")
+	user, domain, _ := strings.Cut(uri, "@")
+	return user, domain
+}
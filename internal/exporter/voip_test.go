@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+func TestCountCallsOncePerCall(t *testing.T) {
+	e := &Exporter{g: gauges{voip: voipGauges{calls: prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "calls_total"}, []string{"type"},
+	)}}}
+	call := func(id int, date bbox.FlexibleInt, number, typ string, answered int) bbox.CallLogEntry {
+		return bbox.CallLogEntry{ID: id, Date: date, Number: number, Type: typ, Answered: answered}
+	}
+	old := call(1, 100, "0100000001", "in", 1)
+	missed := call(1, 200, "0100000002", "in", 0)
+	sameSecond := call(1, 200, "0100000003", "out", 0)
+
+	steps := []struct {
+		name  string
+		calls []bbox.CallLogEntry
+		want  map[string]float64
+	}{
+		{"startup log is not counted", []bbox.CallLogEntry{old}, map[string]float64{}},
+		{"new call after renumbering", []bbox.CallLogEntry{missed, call(2, 100, "0100000001", "in", 1)}, map[string]float64{"missed": 1}},
+		{"unchanged log", []bbox.CallLogEntry{missed, old}, map[string]float64{"missed": 1}},
+		{"call in the same second", []bbox.CallLogEntry{sameSecond, missed, old}, map[string]float64{"missed": 1, "outgoing": 1}},
+		{"rotated log", []bbox.CallLogEntry{call(1, 300, "0100000001", "in", 1), sameSecond}, map[string]float64{"missed": 1, "outgoing": 1, "answered": 1}},
+	}
+	for _, step := range steps {
+		e.countCalls(step.calls)
+		for _, typ := range []string{"missed", "answered", "outgoing"} {
+			if got := testutil.ToFloat64(e.g.voip.calls.WithLabelValues(typ)); got != step.want[typ] {
+				t.Errorf("%s: %s calls = %v, want %v", step.name, typ, got, step.want[typ])
+			}
+		}
+	}
+}
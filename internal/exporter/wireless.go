@@ -0,0 +1,225 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type wirelessRadioGauges struct {
+	enabled           *prometheus.GaugeVec
+	up                *prometheus.GaugeVec
+	channel           *prometheus.GaugeVec
+	configuredChannel *prometheus.GaugeVec
+	channelChanges    *prometheus.CounterVec
+	bandwidth         *prometheus.GaugeVec
+	txPower           *prometheus.GaugeVec
+	dfs               *prometheus.GaugeVec
+	info              *prometheus.GaugeVec
+	ssidEnabled       *prometheus.GaugeVec
+	ssidBroadcast     *prometheus.GaugeVec
+}
+
+type wirelessStatsGauges struct {
+	rxBytes    *prometheus.GaugeVec
+	txBytes    *prometheus.GaugeVec
+	rxMbps     *prometheus.GaugeVec
+	txMbps     *prometheus.GaugeVec
+	rxPackets  *prometheus.GaugeVec
+	txPackets  *prometheus.GaugeVec
+	rxErrors   *prometheus.GaugeVec
+	txErrors   *prometheus.GaugeVec
+	rxDiscards *prometheus.GaugeVec
+	txDiscards *prometheus.GaugeVec
+}
+
+func newWirelessStatsGauges() wirelessStatsGauges {
+	labels := []string{"band", "ssid_id"}
+	return wirelessStatsGauges{
+		rxBytes:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_rx_bytes", Help: "Wi-Fi RX bytes per SSID"}, labels),
+		txBytes:    promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_tx_bytes", Help: "Wi-Fi TX bytes per SSID"}, labels),
+		rxMbps:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_rx_mbps", Help: "Wi-Fi RX throughput in Mbit/s per SSID"}, labels),
+		txMbps:     promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_tx_mbps", Help: "Wi-Fi TX throughput in Mbit/s per SSID"}, labels),
+		rxPackets:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_rx_packets", Help: "Wi-Fi RX packets per SSID"}, labels),
+		txPackets:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_tx_packets", Help: "Wi-Fi TX packets per SSID"}, labels),
+		rxErrors:   promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_rx_packets_errors", Help: "Wi-Fi RX packet errors per SSID"}, labels),
+		txErrors:   promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_tx_packets_errors", Help: "Wi-Fi TX packet errors per SSID"}, labels),
+		rxDiscards: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_rx_packets_discards", Help: "Wi-Fi RX packet discards per SSID"}, labels),
+		txDiscards: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_wireless_stats_tx_packets_discards", Help: "Wi-Fi TX packet discards per SSID"}, labels),
+	}
+}
+
+func newWirelessRadioGauges() wirelessRadioGauges {
+	return wirelessRadioGauges{
+		enabled: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_enabled", Help: "Wi-Fi radio enabled in configuration (1=enabled,0=disabled)"},
+			[]string{"band"},
+		),
+		up: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_up", Help: "Wi-Fi radio operational state (1=up,0=down)"},
+			[]string{"band"},
+		),
+		channel: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_channel", Help: "Wi-Fi radio current channel"},
+			[]string{"band"},
+		),
+		configuredChannel: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_configured_channel", Help: "Wi-Fi radio configured channel (0=automatic)"},
+			[]string{"band"},
+		),
+		channelChanges: promauto.NewCounterVec(
+			prometheus.CounterOpts{Name: "bb_wireless_radio_channel_changes_total", Help: "Current channel changes observed between refreshes"},
+			[]string{"band"},
+		),
+		bandwidth: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_bandwidth_mhz", Help: "Wi-Fi radio channel bandwidth in MHz"},
+			[]string{"band"},
+		),
+		txPower: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_tx_power", Help: "Wi-Fi radio transmit power setting as reported by the BBox"},
+			[]string{"band"},
+		),
+		dfs: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_dfs", Help: "Wi-Fi radio DFS status flag"},
+			[]string{"band"},
+		),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_radio_info", Help: "Wi-Fi radio metadata (labels hold values, gauge is always 1)"},
+			[]string{"band", "standard"},
+		),
+		ssidEnabled: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_ssid_enabled", Help: "SSID enabled flag"},
+			[]string{"band", "ssid"},
+		),
+		ssidBroadcast: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_ssid_broadcast", Help: "SSID broadcast flag (1=visible,0=hidden)"},
+			[]string{"band", "ssid"},
+		),
+	}
+}
+
+// refreshWireless discovers every radio from /api/v1/wireless, updates its configuration
+// gauges and then collects the counters of each SSID the radio reports. A band whose stats
+// cannot be fetched (some firmwares lack /stats for the 6 GHz or guest radio) is logged and
+// skipped; only a failure on every band is an error.
+func (e *Exporter) refreshWireless(ctx context.Context) error {
+	cfg, err := e.client.FetchWirelessConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch wireless config: %w", err)
+	}
+	e.setWirelessConfig(cfg)
+
+	bands := make([]string, 0, len(cfg.Wireless.Radio))
+	for band, r := range cfg.Wireless.Radio {
+		// Disabled radios have no SSID counters to report.
+		if r.Enable == 1 {
+			bands = append(bands, band)
+		}
+	}
+	sort.Strings(bands)
+
+	stats := make(map[string]bbox.WirelessStats, len(bands))
+	for _, band := range bands {
+		s, err := e.client.FetchWirelessStats(ctx, band)
+		if err != nil {
+			log.Printf("fetch wireless %s stats: %v", band, err)
+			continue
+		}
+		stats[band] = s
+	}
+	e.setWirelessStats(stats)
+
+	if len(bands) > 0 && len(stats) == 0 {
+		return fmt.Errorf("fetch wireless stats: no band answered")
+	}
+	return nil
+}
+
+func (e *Exporter) setWirelessStats(stats map[string]bbox.WirelessStats) {
+	g := e.g.wirelessStats
+	g.rxBytes.Reset()
+	g.txBytes.Reset()
+	g.rxMbps.Reset()
+	g.txMbps.Reset()
+	g.rxPackets.Reset()
+	g.txPackets.Reset()
+	g.rxErrors.Reset()
+	g.txErrors.Reset()
+	g.rxDiscards.Reset()
+	g.txDiscards.Reset()
+
+	now := time.Now()
+	seen := make(map[string]rateSample)
+	for band, s := range stats {
+		for _, ssid := range s.Wireless.SSID {
+			id := strconv.Itoa(ssid.ID)
+			rx, tx := ssid.Stats.Rx, ssid.Stats.Tx
+			key := band + "/" + id
+			prev := e.lastWireless[key]
+
+			g.rxBytes.WithLabelValues(band, id).Set(float64(rx.Bytes))
+			g.txBytes.WithLabelValues(band, id).Set(float64(tx.Bytes))
+			g.rxMbps.WithLabelValues(band, id).Set(e.throughputMbps(prev.rxBytes, rx.Bytes, prev.ts, now))
+			g.txMbps.WithLabelValues(band, id).Set(e.throughputMbps(prev.txBytes, tx.Bytes, prev.ts, now))
+			g.rxPackets.WithLabelValues(band, id).Set(float64(rx.Packets))
+			g.txPackets.WithLabelValues(band, id).Set(float64(tx.Packets))
+			g.rxErrors.WithLabelValues(band, id).Set(float64(rx.PacketsErrors))
+			g.txErrors.WithLabelValues(band, id).Set(float64(tx.PacketsErrors))
+			g.rxDiscards.WithLabelValues(band, id).Set(float64(rx.PacketsDiscards))
+			g.txDiscards.WithLabelValues(band, id).Set(float64(tx.PacketsDiscards))
+
+			seen[key] = rateSample{ts: now, rxBytes: rx.Bytes, txBytes: tx.Bytes}
+		}
+	}
+	e.lastWireless = seen
+}
+
+// setWirelessConfig updates per-radio configuration gauges.
+func (e *Exporter) setWirelessConfig(cfg bbox.WirelessConfig) {
+	g := e.g.wirelessRadio
+	g.enabled.Reset()
+	g.up.Reset()
+	g.channel.Reset()
+	g.configuredChannel.Reset()
+	g.bandwidth.Reset()
+	g.txPower.Reset()
+	g.dfs.Reset()
+	g.info.Reset()
+	g.ssidEnabled.Reset()
+	g.ssidBroadcast.Reset()
+
+	channels := make(map[string]bbox.FlexibleInt, len(cfg.Wireless.Radio))
+	for band, r := range cfg.Wireless.Radio {
+		g.enabled.WithLabelValues(band).Set(float64(r.Enable))
+		g.up.WithLabelValues(band).Set(float64(r.State))
+		g.channel.WithLabelValues(band).Set(float64(r.CurrentChannel))
+		g.configuredChannel.WithLabelValues(band).Set(float64(r.Channel))
+		g.bandwidth.WithLabelValues(band).Set(float64(r.HTBW))
+		g.txPower.WithLabelValues(band).Set(float64(r.TxPower))
+		g.dfs.WithLabelValues(band).Set(float64(r.DFS))
+		g.info.WithLabelValues(band, r.Standard).Set(1)
+
+		if prev, ok := e.lastChannels[band]; ok && prev != r.CurrentChannel {
+			g.channelChanges.WithLabelValues(band).Inc()
+		}
+		channels[band] = r.CurrentChannel
+	}
+	e.lastChannels = channels
+
+	for band, s := range cfg.Wireless.SSID {
+		g.ssidEnabled.WithLabelValues(band, s.ID).Set(float64(s.Enable))
+		if s.Hidden == 0 {
+			g.ssidBroadcast.WithLabelValues(band, s.ID).Set(1)
+		} else {
+			g.ssidBroadcast.WithLabelValues(band, s.ID).Set(0)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type wirelessScanGauges struct {
+	neighborRSSI *prometheus.GaugeVec
+	neighbors    *prometheus.GaugeVec
+	congestion   *prometheus.GaugeVec
+	lastScan     prometheus.Gauge
+}
+
+func newWirelessScanGauges() wirelessScanGauges {
+	return wirelessScanGauges{
+		neighborRSSI: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_neighbor_rssi", Help: "RSSI in dBm of neighbouring access points (SSID is hashed)"},
+			[]string{"band", "channel", "ssid_hash"},
+		),
+		neighbors: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_neighbors", Help: "Neighbouring access points seen in the last scan"},
+			[]string{"band"},
+		),
+		congestion: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_wireless_channel_congestion_score", Help: "Signal-weighted neighbour load per channel (higher is busier)"},
+			[]string{"band", "channel"},
+		),
+		lastScan: promauto.NewGauge(prometheus.GaugeOpts{Name: "bb_wireless_scan_last_success_timestamp_seconds", Help: "Unix time of the last successful neighbourhood scan"}),
+	}
+}
+
+// RefreshWirelessScan lists neighbouring access points on every radio. It runs on its own,
+// slower schedule than Refresh because scanning can briefly disturb Wi-Fi clients.
+func (e *Exporter) RefreshWirelessScan(ctx context.Context) error {
+	e.scanMu.Lock()
+	defer e.scanMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cfg, err := e.client.FetchWirelessConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch wireless config: %w", err)
+	}
+
+	bands := make([]string, 0, len(cfg.Wireless.Radio))
+	for band, r := range cfg.Wireless.Radio {
+		if r.Enable == 1 {
+			bands = append(bands, band)
+		}
+	}
+	sort.Strings(bands)
+
+	results := make(map[string][]bbox.WirelessNeighbor, len(bands))
+	for _, band := range bands {
+		scan, err := e.client.FetchWirelessNeighborhood(ctx, band)
+		if err != nil {
+			log.Printf("fetch wireless %s neighborhood: %v", band, err)
+			continue
+		}
+		results[band] = scan.Wireless.Neighborhood
+	}
+	if len(bands) > 0 && len(results) == 0 {
+		return fmt.Errorf("fetch wireless neighborhood: no band answered")
+	}
+
+	g := e.g.wirelessScan
+	g.neighborRSSI.Reset()
+	g.neighbors.Reset()
+	g.congestion.Reset()
+
+	for band, aps := range results {
+		g.neighbors.WithLabelValues(band).Set(float64(len(aps)))
+
+		// Several APs may share an SSID on one channel; keep the strongest.
+		type neighborKey struct {
+			channel  bbox.FlexibleInt
+			ssidHash string
+		}
+		strongest := make(map[neighborKey]bbox.FlexibleInt, len(aps))
+		congestion := make(map[bbox.FlexibleInt]float64)
+		for _, ap := range aps {
+			key := neighborKey{channel: ap.Channel, ssidHash: hashSSID(ap.SSID)}
+			if prev, ok := strongest[key]; !ok || ap.RSSI > prev {
+				strongest[key] = ap.RSSI
+			}
+			for ch, overlap := range overlappingChannels(band, ap.Channel) {
+				congestion[ch] += overlap * signalWeight(ap.RSSI)
+			}
+		}
+		for key, rssi := range strongest {
+			g.neighborRSSI.WithLabelValues(band, strconv.FormatInt(int64(key.channel), 10), key.ssidHash).Set(float64(rssi))
+		}
+		for ch, score := range congestion {
+			g.congestion.WithLabelValues(band, strconv.FormatInt(int64(ch), 10)).Set(score)
+		}
+	}
+	g.lastScan.SetToCurrentTime()
+
+	return nil
+}
+
+// hashSSID keeps neighbour SSIDs out of the metric labels while staying stable across scans.
+func hashSSID(ssid string) string {
+	sum := sha256.Sum256([]byte(ssid))
+	return hex.EncodeToString(sum[:6])
+}
+
+// signalWeight maps an RSSI to [0,1]: -95 dBm (noise floor) counts for nothing, -35 dBm or better for 1.
+func signalWeight(rssi bbox.FlexibleInt) float64 {
+	w := (float64(rssi) + 95) / 60
+	if w < 0 {
+		return 0
+	}
+	if w > 1 {
+		return 1
+	}
+	return w
+}
+
+// overlappingChannels returns the channels an AP interferes with and by how much.
+// 2.4GHz channels are 5MHz apart on 20MHz-wide carriers, so neighbours up to four
+// channels away overlap partially; other bands use non-overlapping channels.
+func overlappingChannels(band string, channel bbox.FlexibleInt) map[bbox.FlexibleInt]float64 {
+	if band != "24" {
+		return map[bbox.FlexibleInt]float64{channel: 1}
+	}
+	out := make(map[bbox.FlexibleInt]float64, 9)
+	for d := bbox.FlexibleInt(-4); d <= 4; d++ {
+		ch := channel + d
+		if ch < 1 || ch > 14 {
+			continue
+		}
+		overlap := 1 - float64(d)/5
+		if d < 0 {
+			overlap = 1 + float64(d)/5
+		}
+		out[ch] = overlap
+	}
+	return out
+}
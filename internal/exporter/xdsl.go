@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dsegura/bbox-exporter/internal/bbox"
+)
+
+type xdslGauges struct {
+	up             *prometheus.GaugeVec
+	showtime       *prometheus.GaugeVec
+	syncCount      *prometheus.GaugeVec
+	syncRate       *prometheus.GaugeVec
+	attainableRate *prometheus.GaugeVec
+	snrMargin      *prometheus.GaugeVec
+	attenuation    *prometheus.GaugeVec
+	power          *prometheus.GaugeVec
+	errors         *prometheus.GaugeVec
+	info           *prometheus.GaugeVec
+}
+
+func newXDSLGauges() xdslGauges {
+	return xdslGauges{
+		// Label-less vecs so the series disappear on boxes without an xDSL line.
+		up:        promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_xdsl_up", Help: "xDSL line synchronised (1=Connected,0=other)"}, nil),
+		showtime:  promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_xdsl_showtime_seconds", Help: "xDSL line uptime since last sync"}, nil),
+		syncCount: promauto.NewGaugeVec(prometheus.GaugeOpts{Name: "bb_xdsl_sync_count", Help: "xDSL line synchronisations since boot"}, nil),
+		syncRate: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_sync_rate_bps", Help: "xDSL synchronised bitrate in bit/s"},
+			[]string{"direction"},
+		),
+		attainableRate: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_attainable_rate_bps", Help: "xDSL maximum attainable bitrate in bit/s"},
+			[]string{"direction"},
+		),
+		snrMargin: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_snr_margin_db", Help: "xDSL SNR margin in dB"},
+			[]string{"direction"},
+		),
+		attenuation: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_attenuation_db", Help: "xDSL line attenuation in dB"},
+			[]string{"direction"},
+		),
+		power: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_power_dbm", Help: "xDSL output power in dBm"},
+			[]string{"direction"},
+		),
+		errors: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_errors", Help: "xDSL error counters by kind (crc, fec, hec) and side (local, remote)"},
+			[]string{"kind", "side"},
+		),
+		info: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "bb_xdsl_info", Help: "xDSL line metadata (labels hold values, gauge is always 1)"},
+			[]string{"state", "modulation"},
+		),
+	}
+}
+
+func (g xdslGauges) reset() {
+	for _, v := range []*prometheus.GaugeVec{
+		g.up, g.showtime, g.syncCount, g.syncRate, g.attainableRate, g.snrMargin, g.attenuation, g.power, g.errors, g.info,
+	} {
+		v.Reset()
+	}
+}
+
+// refreshXDSL updates line and error gauges from /api/v1/xdsl and /api/v1/xdsl/stats.
+// Boxes without an xDSL line (FTTH) answer 404 and export no xDSL series.
+func (e *Exporter) refreshXDSL(ctx context.Context) error {
+	g := e.g.xdsl
+	info, err := e.client.FetchXDSLInfo(ctx)
+	if unsupported(err) {
+		g.reset()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetch xdsl: %w", err)
+	}
+	stats, err := e.client.FetchXDSLStats(ctx)
+	if unsupported(err) {
+		g.reset()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetch xdsl stats: %w", err)
+	}
+
+	line := info.Wan.XDSL
+	if strings.EqualFold(line.State, "connected") {
+		g.up.WithLabelValues().Set(1)
+	} else {
+		g.up.WithLabelValues().Set(0)
+	}
+	g.showtime.WithLabelValues().Set(float64(line.ShowTime))
+	g.syncCount.WithLabelValues().Set(float64(line.SyncCount))
+
+	for direction, d := range map[string]bbox.XDSLDirection{"up": line.Up, "down": line.Down} {
+		// Bitrates are reported in kbit/s.
+		g.syncRate.WithLabelValues(direction).Set(float64(d.Bitrates) * 1000)
+		g.attainableRate.WithLabelValues(direction).Set(float64(d.MaxBitrates) * 1000)
+		g.snrMargin.WithLabelValues(direction).Set(float64(d.Noise))
+		g.attenuation.WithLabelValues(direction).Set(float64(d.Attenuation))
+		g.power.WithLabelValues(direction).Set(float64(d.Power))
+	}
+
+	counters := stats.Wan.XDSL.Stats
+	g.errors.WithLabelValues("crc", "local").Set(float64(counters.LocalCRC))
+	g.errors.WithLabelValues("crc", "remote").Set(float64(counters.RemoteCRC))
+	g.errors.WithLabelValues("fec", "local").Set(float64(counters.LocalFEC))
+	g.errors.WithLabelValues("fec", "remote").Set(float64(counters.RemoteFEC))
+	g.errors.WithLabelValues("hec", "local").Set(float64(counters.LocalHEC))
+	g.errors.WithLabelValues("hec", "remote").Set(float64(counters.RemoteHEC))
+
+	g.info.Reset()
+	g.info.WithLabelValues(line.State, line.Modulation).Set(1)
+
+	return nil
+}